@@ -0,0 +1,145 @@
+// Package analyzer provides a go/analysis Analyzer that checks config structs are documented and
+// tagged the way prettyconf expects, so that problems surface in editors, `go vet` and CI.
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// Analyzer checks the exported fields of config structs, which are structs with at least one field
+// with a `json` or `prettyconf` tag. It reports:
+//
+//   - fields without a doc comment
+//   - doc comments that do not start with the field name
+//   - json names that are not camelCase
+//   - json names used by more than one field, compared case-insensitively as encoding/json does
+//   - struct tags that are malformed, or have an invalid prettyconf tag
+//   - omitempty on struct values, where it has no effect
+var Analyzer = &analysis.Analyzer{
+	Name: "prettyconf",
+	Doc:  "check that config structs are documented and tagged for prettyconf",
+	Run:  run,
+}
+
+var camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			typeSpec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if structType, ok := typeSpec.Type.(*ast.StructType); ok && isConfigStruct(structType) {
+				checkStruct(pass, typeSpec.Name.Name, structType)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+func isConfigStruct(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		tags, _ := loader.ParseStructTags(structTag(field))
+		if tags.Has("json") || tags.Has(loader.PrettyconfTagName) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkStruct(pass *analysis.Pass, structName string, structType *ast.StructType) {
+	wireNames := map[string]string{}
+	for _, field := range structType.Fields.List {
+		tags, valid := fieldTags(pass, field)
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			checkDoc(pass, structName, name, field.Doc)
+
+			if !valid || tags.JSONProperty == "-" {
+				continue
+			}
+			wireName := tags.JSONProperty
+			if wireName == "" {
+				wireName = name.Name
+			} else if !camelCase.MatchString(wireName) {
+				pass.Reportf(field.Tag.Pos(), "json name %q of %s.%s is not camelCase", wireName, structName, name.Name)
+			}
+			if other, ok := wireNames[strings.ToLower(wireName)]; ok {
+				pass.Reportf(name.Pos(), "json name %q of %s.%s is already used by %s", wireName, structName, name.Name, other)
+			} else {
+				wireNames[strings.ToLower(wireName)] = name.Name
+			}
+			if !tags.Required && isStructValue(pass.TypesInfo.TypeOf(field.Type)) {
+				pass.Reportf(field.Tag.Pos(), "omitempty has no effect on struct field %s.%s, use a pointer", structName, name.Name)
+			}
+		}
+	}
+}
+
+// fieldTags parses the tags of field as the loader does, reporting malformed struct tags and
+// invalid prettyconf tags. No field name is passed to the loader, so fields without a json name
+// have an empty JSONProperty. It returns false if the tags could not be parsed.
+func fieldTags(pass *analysis.Pass, field *ast.Field) (loader.FieldTags, bool) {
+	tag := structTag(field)
+	validErr := loader.ValidateStructTag(tag)
+	if validErr != nil {
+		pass.Reportf(field.Tag.Pos(), "%v", validErr)
+	}
+	tags, err := loader.ParseFieldTags("", tag)
+	if err != nil {
+		// Struct tags that cannot be parsed are malformed, so only prettyconf tag errors are
+		// left to report.
+		if validErr == nil {
+			pass.Reportf(field.Tag.Pos(), "%v", err)
+		}
+		return loader.FieldTags{}, false
+	}
+	return tags, true
+}
+
+func checkDoc(pass *analysis.Pass, structName string, name *ast.Ident, doc *ast.CommentGroup) {
+	if doc == nil {
+		pass.Reportf(name.Pos(), "exported config field %s.%s should have a comment", structName, name.Name)
+		return
+	}
+	text, _ := loader.ExtractMarkers(doc.Text())
+	if text == "" {
+		pass.Reportf(name.Pos(), "exported config field %s.%s should have a comment", structName, name.Name)
+		return
+	}
+	if !strings.HasPrefix(text, name.Name+" ") && text != name.Name {
+		pass.Reportf(doc.Pos(), "comment on exported config field %s.%s should be of the form \"%s ...\"", structName, name.Name, name.Name)
+	}
+}
+
+func structTag(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+func isStructValue(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
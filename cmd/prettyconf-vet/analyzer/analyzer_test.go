@@ -0,0 +1,19 @@
+package analyzer_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/jimmidyson/prettyconf/cmd/prettyconf-vet/analyzer"
+)
+
+var _ = Describe("Analyzer", func() {
+	It("reports config struct problems", func() {
+		testdata, err := filepath.Abs("testdata")
+		Expect(err).NotTo(HaveOccurred())
+		analysistest.Run(GinkgoT(), testdata, analyzer.Analyzer, "a")
+	})
+})
@@ -0,0 +1,39 @@
+package a
+
+// Config is a config struct.
+type Config struct {
+	// Name is the name.
+	Name string `json:"name"`
+	Port int    `json:"port"` // want `exported config field Config.Port should have a comment`
+	// The host to connect to. // want `comment on exported config field Config.Host should be of the form "Host ..."`
+	Host string `json:"host"`
+	// Listen_Address is the address to listen on.
+	Listen_Address string `json:"listen_address"` // want `json name "listen_address" of Config.Listen_Address is not camelCase`
+	// Alias is another name.
+	Alias string `json:"Name"` // want `json name "Name" of Config.Alias is not camelCase` `json name "Name" of Config.Alias is already used by Name`
+	// Secret is malformed.
+	Secret string `json:"secret" prettyconf:secret` // want "malformed struct tag at `prettyconf:secret`"
+	// Order is invalid.
+	Order int `json:"order" prettyconf:"order=first"` // want "failed to parse struct tag `json:\"order\" prettyconf:\"order=first\"`: invalid order \"first\""
+	// Nested is a struct value.
+	Nested Nested `json:"nested,omitempty"` // want `omitempty has no effect on struct field Config.Nested, use a pointer`
+	// NestedPtr is a struct pointer.
+	NestedPtr *Nested `json:"nestedPtr,omitempty"`
+	// Hidden is not serialized.
+	Hidden string `json:"-"`
+	// Token is documented.
+	// +sensitive
+	Token    string `json:"token"`
+	internal string
+}
+
+// Nested is a nested config struct.
+type Nested struct {
+	// Value is a value.
+	Value string `json:"value,omitempty"`
+}
+
+// NotConfig has no tags so is not checked.
+type NotConfig struct {
+	Value string
+}
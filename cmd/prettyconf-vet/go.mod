@@ -0,0 +1,29 @@
+module github.com/jimmidyson/prettyconf/cmd/prettyconf-vet
+
+go 1.25.0
+
+require (
+	github.com/jimmidyson/prettyconf v0.0.0-00010101000000-000000000000
+	github.com/onsi/ginkgo v1.10.2
+	github.com/onsi/gomega v1.7.0
+	golang.org/x/tools v0.45.0
+)
+
+require (
+	github.com/go-logr/logr v0.1.0 // indirect
+	github.com/hpcloud/tail v1.0.0 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	golang.org/x/mod v0.36.0 // indirect
+	golang.org/x/net v0.54.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+	golang.org/x/sys v0.44.0 // indirect
+	golang.org/x/text v0.37.0 // indirect
+	gopkg.in/fsnotify.v1 v1.4.7 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v2 v2.2.1 // indirect
+)
+
+// The analyzer is developed alongside the library. It is a separate module so that the newer
+// golang.org/x/tools it needs to load packages built by current Go toolchains does not raise the
+// minimum Go version of the library.
+replace github.com/jimmidyson/prettyconf => ../..
@@ -0,0 +1,20 @@
+// Command prettyconf-vet checks that config structs are documented and tagged for prettyconf.
+//
+// It can be run directly on packages, or by go vet:
+//
+//	prettyconf-vet ./...
+//	go vet -vettool=$(which prettyconf-vet) ./...
+//
+// It is built as a separate module, as it needs a newer Go than the library. Install it by running
+// `go install .` in this directory.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/jimmidyson/prettyconf/cmd/prettyconf-vet/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
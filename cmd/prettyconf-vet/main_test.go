@@ -0,0 +1,45 @@
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var toolDir, tool string
+
+var _ = BeforeSuite(func() {
+	var err error
+	toolDir, err = ioutil.TempDir("", "prettyconf-vet")
+	Expect(err).NotTo(HaveOccurred())
+
+	tool = filepath.Join(toolDir, "prettyconf-vet")
+	output, err := exec.Command("go", "build", "-o", tool, ".").CombinedOutput()
+	Expect(err).NotTo(HaveOccurred(), string(output))
+})
+
+var _ = AfterSuite(func() {
+	Expect(os.RemoveAll(toolDir)).To(Succeed())
+})
+
+var _ = Describe("prettyconf-vet", func() {
+	It("reports problems when run directly", func() {
+		cmd := exec.Command(tool, "./...")
+		cmd.Dir = "testdata"
+		output, err := cmd.CombinedOutput()
+		Expect(err).To(HaveOccurred())
+		Expect(string(output)).To(ContainSubstring("config.go:5:2: exported config field Config.Port should have a comment"))
+	})
+
+	It("reports problems when run by go vet", func() {
+		cmd := exec.Command("go", "vet", "-vettool="+tool, "./...")
+		cmd.Dir = "testdata"
+		output, err := cmd.CombinedOutput()
+		Expect(err).To(HaveOccurred())
+		Expect(string(output)).To(ContainSubstring("config.go:5:2: exported config field Config.Port should have a comment"))
+	})
+})
@@ -0,0 +1,13 @@
+package main_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPrettyconfVet(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Prettyconf Vet Suite")
+}
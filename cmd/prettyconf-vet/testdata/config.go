@@ -0,0 +1,6 @@
+package vettest
+
+// Config is a config.
+type Config struct {
+	Port int `json:"port"`
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jimmidyson/prettyconf/pkg/changelog"
+)
+
+func runChangelog(args []string) error {
+	fs := flag.NewFlagSet("changelog", flag.ExitOnError)
+	typeName := fs.String("type", "", "the config type to compare when comparing directories")
+	oldDir := fs.String("old-dir", "", "the directory to load the old version of the config type from")
+	newDir := fs.String("new-dir", "", "the directory to load the new version of the config type from")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf changelog old.json new.json")
+		fmt.Fprintln(fs.Output(), "       prettyconf changelog -type <import path>.<name> -old-dir dir -new-dir dir")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Changelog writes the configuration changes between two snapshots, or the config type in two")
+		fmt.Fprintln(fs.Output(), "checkouts, as Markdown for release notes.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	old, new, err := diffSnapshots(fs, *typeName, *oldDir, *newDir, *verbosity)
+	if err != nil {
+		return err
+	}
+	return changelog.Write(os.Stdout, old, new)
+}
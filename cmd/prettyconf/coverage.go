@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jimmidyson/prettyconf/pkg/coverage"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+func runCoverage(args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "write the report as JSON instead of a table")
+	minimum := fs.Float64("min", 0, "the minimum coverage percentage, below which the command fails")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf coverage [-json] [-min percent] package...")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Coverage reports how many of the config types and fields in the packages are documented.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	logger := &writerLogger{w: os.Stderr, verbosity: *verbosity}
+	packages, err := loader.New(fs.Args(), logger).Load()
+	if err != nil {
+		return err
+	}
+
+	report := coverage.New(packages)
+	if *jsonOutput {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteTable(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	return report.Check(*minimum)
+}
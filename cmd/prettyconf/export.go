@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/model"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "", "the file to write the catalog to, instead of standard output")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf export [-o file] package...")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Export writes the config types in the packages as a JSON catalog, described by the")
+		fmt.Fprintln(fs.Output(), "github.com/jimmidyson/prettyconf/pkg/model package, for tools that cannot load Go source.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	logger := &writerLogger{w: os.Stderr, verbosity: *verbosity}
+	packages, err := loader.New(fs.Args(), logger).Load()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return model.New(packages).Write(w)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-logr/logr"
+)
+
+// writerLogger is a logr.Logger that writes messages up to a verbosity level to a writer.
+type writerLogger struct {
+	w             io.Writer
+	verbosity     int
+	level         int
+	keysAndValues []interface{}
+}
+
+var _ logr.Logger = &writerLogger{}
+
+func (l *writerLogger) Enabled() bool {
+	return l.level <= l.verbosity
+}
+
+func (l *writerLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.Enabled() {
+		fmt.Fprintln(l.w, msg, l.keysAndValues, keysAndValues)
+	}
+}
+
+func (l *writerLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	fmt.Fprintln(l.w, msg, err, l.keysAndValues, keysAndValues)
+}
+
+func (l *writerLogger) V(level int) logr.InfoLogger {
+	v := *l
+	v.level = level
+	return &v
+}
+
+func (l *writerLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	v := *l
+	v.keysAndValues = append(l.keysAndValues[:len(l.keysAndValues):len(l.keysAndValues)], keysAndValues...)
+	return &v
+}
+
+func (l *writerLogger) WithName(name string) logr.Logger {
+	return l
+}
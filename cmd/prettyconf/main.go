@@ -0,0 +1,98 @@
+// Command prettyconf works with config documents using the docs and markers of the Go types they
+// are loaded into.
+//
+// Usage:
+//
+//	prettyconf <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	migrate    rewrite config documents into the current shape of a config type
+//	coverage   report how much of the config types in packages is documented
+//	snapshot   record the keys of a config type
+//	diff       compare two snapshots and report breaking changes
+//	changelog  write the changes between two snapshots as Markdown
+//	export     write the config types in packages as a JSON catalog
+//	site       write a static HTML reference of a config type
+//	schema     write a JSON Schema of a config type
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "migrate", usage: "rewrite config documents into the current shape of a config type", run: runMigrate},
+	{name: "coverage", usage: "report how much of the config types in packages is documented", run: runCoverage},
+	{name: "snapshot", usage: "record the keys of a config type", run: runSnapshot},
+	{name: "diff", usage: "compare two snapshots and report breaking changes", run: runDiff},
+	{name: "changelog", usage: "write the changes between two snapshots as Markdown", run: runChangelog},
+	{name: "export", usage: "write the config types in packages as a JSON catalog", run: runExport},
+	{name: "site", usage: "write a static HTML reference of a config type", run: runSite},
+	{name: "schema", usage: "write a JSON Schema of a config type", run: runSchema},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != flag.Arg(0) {
+			continue
+		}
+		if err := cmd.run(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "prettyconf %s: %v\n", cmd.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "prettyconf: unknown command %q\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: prettyconf <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// loadType loads the type named by qualifiedName, e.g. `github.com/org/app/config.Config`.
+func loadType(qualifiedName string, logger logr.Logger, opts ...loader.Option) (loader.Type, []loader.Package, error) {
+	idx := strings.LastIndex(qualifiedName, ".")
+	if idx <= 0 || idx == len(qualifiedName)-1 {
+		return loader.Type{}, nil, errors.Errorf("type %q must be written as <import path>.<name>", qualifiedName)
+	}
+	pkgPath, name := qualifiedName[:idx], qualifiedName[idx+1:]
+
+	packages, err := loader.New([]string{pkgPath}, logger, opts...).Load()
+	if err != nil {
+		return loader.Type{}, nil, errors.Wrapf(err, "failed to load package %s", pkgPath)
+	}
+	rootType, found := loader.FindType(packages, pkgPath, name)
+	if !found {
+		return loader.Type{}, nil, errors.Errorf("type %s could not be found", qualifiedName)
+	}
+	return rootType, packages, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/migrate"
+)
+
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	typeName := fs.String("type", "", "the config type to migrate to, e.g. github.com/org/app/config.Config")
+	write := fs.Bool("w", false, "write the migrated documents back to their files instead of to stdout")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf migrate -type <import path>.<name> [-w] file...")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Migrate rewrites YAML or JSON config documents into the current shape of the config type,")
+		fmt.Fprintln(fs.Output(), "following the +renamedFrom and +movedFrom markers on its fields. Each change is reported")
+		fmt.Fprintln(fs.Output(), "on stderr.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *typeName == "" || fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	logger := &writerLogger{w: os.Stderr, verbosity: *verbosity}
+	rootType, packages, err := loadType(*typeName, logger)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range fs.Args() {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		migrated, changes, err := migrate.Migrate(data, rootType, packages)
+		if err != nil {
+			return errors.Wrapf(err, "failed to migrate %s", file)
+		}
+		for _, change := range changes {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", file, change)
+		}
+		if !*write {
+			os.Stdout.Write(migrated)
+			continue
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file, migrated, info.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/schema"
+)
+
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	typeName := fs.String("type", "", "the config type to describe, e.g. github.com/org/app/config.Config")
+	output := fs.String("o", "", "the file to write the schema to, instead of standard output")
+	catalog := fs.String("catalog", "", "a catalog written by prettyconf export to read the type from, instead of loading its source")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf schema -type <import path>.<name> [-o file] [-catalog file]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Schema writes a JSON Schema of the config type, for editors and tools that validate config")
+		fmt.Fprintln(fs.Output(), "documents. Unions require exactly one of their members.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *typeName == "" || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	logger := &writerLogger{w: os.Stderr, verbosity: *verbosity}
+	var (
+		rootType loader.Type
+		packages []loader.Package
+		err      error
+	)
+	if *catalog != "" {
+		rootType, packages, err = readCatalogType(*catalog, *typeName)
+	} else {
+		rootType, packages, err = loadType(*typeName, logger)
+	}
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return schema.Write(w, rootType, packages)
+}
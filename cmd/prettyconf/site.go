@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/model"
+	"github.com/jimmidyson/prettyconf/pkg/site"
+)
+
+func runSite(args []string) error {
+	fs := flag.NewFlagSet("site", flag.ExitOnError)
+	typeName := fs.String("type", "", "the config type to document, e.g. github.com/org/app/config.Config")
+	output := fs.String("o", "site", "the directory to write the site to")
+	catalog := fs.String("catalog", "", "a catalog written by prettyconf export to read the type from, instead of loading its source")
+	title := fs.String("title", "", "the title of the page")
+	sourceURL := fs.String("source-url", "", "the URL to link source positions to, with {package}, {file} and {line} replaced, "+
+		"e.g. https://github.com/org/app/blob/main/{file}#L{line}")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf site -type <import path>.<name> [-o dir] [-catalog file] [-source-url url]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Site writes a self-contained HTML reference of the config type to index.html in the directory.")
+		fmt.Fprintln(fs.Output(), "{file} is the path of the source file relative to the current directory, or its name for")
+		fmt.Fprintln(fs.Output(), "catalogs. Without -source-url source positions link to files relative to the directory.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *typeName == "" || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	logger := &writerLogger{w: os.Stderr, verbosity: *verbosity}
+	var (
+		rootType loader.Type
+		packages []loader.Package
+		err      error
+	)
+	if *catalog != "" {
+		rootType, packages, err = readCatalogType(*catalog, *typeName)
+	} else {
+		rootType, packages, err = loadType(*typeName, logger)
+	}
+	if err != nil {
+		return err
+	}
+
+	var opts []site.Option
+	if *title != "" {
+		opts = append(opts, site.WithTitle(*title))
+	}
+	if *sourceURL != "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, site.WithSourceURL(func(pkgPath string, position loader.Position) string {
+			file := position.Filename
+			if rel, err := filepath.Rel(cwd, file); err == nil && filepath.IsAbs(file) {
+				file = filepath.ToSlash(rel)
+			}
+			return strings.NewReplacer("{package}", pkgPath, "{file}", file, "{line}", strconv.Itoa(position.Line)).Replace(*sourceURL)
+		}))
+	}
+	return site.Generate(*output, rootType, packages, opts...)
+}
+
+// readCatalogType reads the type named by qualifiedName from the catalog file.
+func readCatalogType(file, qualifiedName string) (loader.Type, []loader.Package, error) {
+	idx := strings.LastIndex(qualifiedName, ".")
+	if idx <= 0 || idx == len(qualifiedName)-1 {
+		return loader.Type{}, nil, errors.Errorf("type %q must be written as <import path>.<name>", qualifiedName)
+	}
+	pkgPath, name := qualifiedName[:idx], qualifiedName[idx+1:]
+
+	f, err := os.Open(file)
+	if err != nil {
+		return loader.Type{}, nil, err
+	}
+	defer f.Close()
+	c, err := model.Read(f)
+	if err != nil {
+		return loader.Type{}, nil, errors.Wrapf(err, "failed to read catalog %s", file)
+	}
+	packages, err := c.LoaderPackages()
+	if err != nil {
+		return loader.Type{}, nil, errors.Wrapf(err, "invalid catalog %s", file)
+	}
+	rootType, found := loader.FindType(packages, pkgPath, name)
+	if !found {
+		return loader.Type{}, nil, errors.Errorf("type %s could not be found in catalog %s", qualifiedName, file)
+	}
+	return rootType, packages, nil
+}
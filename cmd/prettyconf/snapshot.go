@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/snapshot"
+)
+
+func runSnapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	typeName := fs.String("type", "", "the config type to snapshot, e.g. github.com/org/app/config.Config")
+	dir := fs.String("dir", "", "the directory to load the package from, defaults to the current directory")
+	output := fs.String("o", "", "the file to write the snapshot to, defaults to stdout")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf snapshot -type <import path>.<name> [-dir dir] [-o file]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Snapshot records the keys of the config type as JSON, for comparing with prettyconf diff.")
+		fmt.Fprintln(fs.Output(), "Snapshots taken by the command have the defaults set with +default markers.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *typeName == "" || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	s, err := takeSnapshot(*typeName, *dir, *verbosity)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		return s.Write(os.Stdout)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.Write(f)
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	typeName := fs.String("type", "", "the config type to compare when comparing directories")
+	oldDir := fs.String("old-dir", "", "the directory to load the old version of the config type from")
+	newDir := fs.String("new-dir", "", "the directory to load the new version of the config type from")
+	jsonOutput := fs.Bool("json", false, "write the changes as JSON")
+	verbosity := fs.Int("v", 0, "the log verbosity")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prettyconf diff [-json] old.json new.json")
+		fmt.Fprintln(fs.Output(), "       prettyconf diff [-json] -type <import path>.<name> -old-dir dir -new-dir dir")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Diff compares two snapshots, or the config type in two checkouts such as git worktrees,")
+		fmt.Fprintln(fs.Output(), "and exits with status 1 if any change is breaking. Snapshots taken from checkouts have the")
+		fmt.Fprintln(fs.Output(), "defaults set with +default markers.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	old, new, err := diffSnapshots(fs, *typeName, *oldDir, *newDir, *verbosity)
+	if err != nil {
+		return err
+	}
+
+	changes := snapshot.Diff(old, new)
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if changes == nil {
+			changes = []snapshot.Change{}
+		}
+		if err := encoder.Encode(changes); err != nil {
+			return err
+		}
+	} else {
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+	}
+	if snapshot.HasBreaking(changes) {
+		return errors.New("breaking changes found")
+	}
+	return nil
+}
+
+// diffSnapshots returns the snapshots to compare, read from the two file arguments or taken from
+// the config type in the two directories.
+func diffSnapshots(fs *flag.FlagSet, typeName, oldDir, newDir string, verbosity int) (snapshot.Snapshot, snapshot.Snapshot, error) {
+	switch {
+	case typeName != "" && oldDir != "" && newDir != "" && fs.NArg() == 0:
+		old, err := takeSnapshot(typeName, oldDir, verbosity)
+		if err != nil {
+			return snapshot.Snapshot{}, snapshot.Snapshot{}, err
+		}
+		new, err := takeSnapshot(typeName, newDir, verbosity)
+		return old, new, err
+	case typeName == "" && oldDir == "" && newDir == "" && fs.NArg() == 2:
+		old, err := readSnapshot(fs.Arg(0))
+		if err != nil {
+			return snapshot.Snapshot{}, snapshot.Snapshot{}, err
+		}
+		new, err := readSnapshot(fs.Arg(1))
+		return old, new, err
+	default:
+		fs.Usage()
+		os.Exit(2)
+		return snapshot.Snapshot{}, snapshot.Snapshot{}, nil
+	}
+}
+
+// takeSnapshot snapshots typeName as loaded from dir. Config values are only known to the program
+// that builds the config, so the snapshot has the defaults set with `+default` markers.
+func takeSnapshot(typeName, dir string, verbosity int) (snapshot.Snapshot, error) {
+	logger := &writerLogger{w: os.Stderr, verbosity: verbosity}
+	rootType, packages, err := loadType(typeName, logger, loader.WithDir(dir))
+	if err != nil {
+		return snapshot.Snapshot{}, err
+	}
+	return snapshot.New(rootType, packages, nil)
+}
+
+func readSnapshot(file string) (snapshot.Snapshot, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return snapshot.Snapshot{}, err
+	}
+	defer f.Close()
+	s, err := snapshot.Read(f)
+	return s, errors.Wrapf(err, "failed to read %s", file)
+}
@@ -0,0 +1,169 @@
+// Package changelog describes the changes between two snapshots of a config type as Markdown, for
+// the configuration section of release notes.
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jimmidyson/prettyconf/pkg/snapshot"
+)
+
+// Category is a group of changes in the changelog.
+type Category string
+
+const (
+	Added      Category = "Added"
+	Deprecated Category = "Deprecated"
+	Removed    Category = "Removed"
+	Changed    Category = "Changed"
+)
+
+// categories is the order categories are written in.
+var categories = []Category{Added, Deprecated, Removed, Changed}
+
+// Entry is a changed key in the changelog.
+type Entry struct {
+	Category Category
+	// Type is the name of the type declaring the key.
+	Type string
+	Path string
+	Doc  string
+	// Descriptions describe the changes to the key.
+	Descriptions []string
+	Breaking     bool
+}
+
+// Entries returns the changelog entries for the changes from old to new, with a single entry per
+// key and category.
+func Entries(old, new snapshot.Snapshot) []Entry {
+	var entries []Entry
+	index := map[string]int{}
+	for _, c := range snapshot.Diff(old, new) {
+		category, description := describe(c)
+		key, found := new.Key(c.Path)
+		if !found {
+			key, _ = old.Key(c.Path)
+		}
+
+		id := string(category) + " " + c.Path
+		i, ok := index[id]
+		if !ok {
+			i = len(entries)
+			index[id] = i
+			entries = append(entries, Entry{Category: category, Type: typeName(key.Owner), Path: c.Path, Doc: key.Doc})
+		}
+		if description != "" {
+			entries[i].Descriptions = append(entries[i].Descriptions, description)
+		}
+		entries[i].Breaking = entries[i].Breaking || c.Breaking
+	}
+	return entries
+}
+
+func describe(c snapshot.Change) (Category, string) {
+	switch c.Kind {
+	case snapshot.KeyAdded:
+		if c.Breaking {
+			return Added, "Required."
+		}
+		return Added, ""
+	case snapshot.KeyRemoved:
+		return Removed, ""
+	case snapshot.KeyDeprecated:
+		return Deprecated, c.New
+	case snapshot.KeyRenamed:
+		return Changed, fmt.Sprintf("Renamed from `%s`.", c.OldPath)
+	case snapshot.TypeChanged:
+		return Changed, fmt.Sprintf("Type changed from %s to %s.", c.Old, c.New)
+	case snapshot.BecameRequired:
+		return Changed, "Now required."
+	case snapshot.BecameOptional:
+		return Changed, "Now optional."
+	case snapshot.EnumValuesRemoved:
+		return Changed, fmt.Sprintf("Values %s are no longer allowed.", codeList(c.Old))
+	case snapshot.EnumValuesAdded:
+		return Changed, fmt.Sprintf("Values %s are now allowed.", codeList(c.New))
+	case snapshot.DefaultChanged:
+		return Changed, fmt.Sprintf("Default changed from `%s` to `%s`.", c.Old, c.New)
+	default:
+		return Changed, string(c.Kind)
+	}
+}
+
+// Write writes the Markdown changelog of the changes from old to new to w. Entries are grouped by
+// the type declaring the key, in the order the types are first changed, and then by category.
+// Breaking changes are marked in bold.
+func Write(w io.Writer, old, new snapshot.Snapshot) error {
+	entries := Entries(old, new)
+	fmt.Fprintln(w, "## Configuration changes")
+	fmt.Fprintln(w)
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No configuration changes.")
+		return nil
+	}
+
+	var typeNames []string
+	byType := map[string][]Entry{}
+	for _, e := range entries {
+		if _, ok := byType[e.Type]; !ok {
+			typeNames = append(typeNames, e.Type)
+		}
+		byType[e.Type] = append(byType[e.Type], e)
+	}
+
+	for i, name := range typeNames {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "### %s\n", name)
+		for _, category := range categories {
+			var lines []string
+			for _, e := range byType[name] {
+				if e.Category == category {
+					lines = append(lines, entryLine(e))
+				}
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "\n#### %s\n\n", category)
+			for _, line := range lines {
+				fmt.Fprintln(w, line)
+			}
+		}
+	}
+	return nil
+}
+
+func entryLine(e Entry) string {
+	line := fmt.Sprintf("- `%s`", e.Path)
+	if e.Breaking {
+		line += " **(breaking)**"
+	}
+	if e.Doc != "" {
+		line += ": " + strings.Join(strings.Fields(e.Doc), " ")
+	}
+	if len(e.Descriptions) > 0 {
+		separator := " "
+		if e.Doc == "" {
+			separator = ": "
+		}
+		line += separator + strings.Join(e.Descriptions, " ")
+	}
+	return line
+}
+
+// typeName returns the name of the import path qualified type name.
+func typeName(qualifiedName string) string {
+	return qualifiedName[strings.LastIndex(qualifiedName, ".")+1:]
+}
+
+func codeList(values string) string {
+	split := strings.Split(values, ",")
+	for i := range split {
+		split[i] = "`" + split[i] + "`"
+	}
+	return strings.Join(split, ", ")
+}
@@ -0,0 +1,39 @@
+package changelog_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/changelog"
+	"github.com/jimmidyson/prettyconf/pkg/snapshot"
+	v1 "github.com/jimmidyson/prettyconf/pkg/snapshot/testdata/v1"
+	v2 "github.com/jimmidyson/prettyconf/pkg/snapshot/testdata/v2"
+)
+
+var _ = Describe("Changelog", func() {
+	It("writes changes grouped by type", func() {
+		old, err := snapshot.For(v1.Config{Timeout: 30}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		new, err := snapshot.For(v2.Config{Timeout: "30s"}, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(changelog.Write(&buf, old, new)).To(Succeed())
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "changelog.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal(string(expected)))
+	})
+
+	It("writes a note when nothing changed", func() {
+		s, err := snapshot.For(v1.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(changelog.Write(&buf, s, s)).To(Succeed())
+		Expect(buf.String()).To(Equal("## Configuration changes\n\nNo configuration changes.\n"))
+	})
+})
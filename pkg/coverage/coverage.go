@@ -0,0 +1,144 @@
+// Package coverage reports how much of a set of config types is documented.
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// Counts holds the number of types and fields and how many of them are documented.
+type Counts struct {
+	Types            int `json:"types"`
+	DocumentedTypes  int `json:"documentedTypes"`
+	Fields           int `json:"fields"`
+	DocumentedFields int `json:"documentedFields"`
+}
+
+// Percent returns the percentage of types and fields that are documented, or 100 if there are none.
+func (c Counts) Percent() float64 {
+	total := c.Types + c.Fields
+	if total == 0 {
+		return 100
+	}
+	return float64(c.DocumentedTypes+c.DocumentedFields) * 100 / float64(total)
+}
+
+func (c *Counts) add(other Counts) {
+	c.Types += other.Types
+	c.DocumentedTypes += other.DocumentedTypes
+	c.Fields += other.Fields
+	c.DocumentedFields += other.DocumentedFields
+}
+
+// Report is the documentation coverage of a set of packages.
+type Report struct {
+	Total    Counts          `json:"total"`
+	Packages []PackageReport `json:"packages"`
+}
+
+// PackageReport is the documentation coverage of a package.
+type PackageReport struct {
+	Path     string       `json:"path"`
+	Coverage Counts       `json:"coverage"`
+	Types    []TypeReport `json:"types"`
+}
+
+// TypeReport is the documentation coverage of a type.
+type TypeReport struct {
+	Name     string `json:"name"`
+	Coverage Counts `json:"coverage"`
+	// UndocumentedFields holds the JSON names of the fields without docs.
+	UndocumentedFields []string `json:"undocumentedFields,omitempty"`
+}
+
+// MarshalJSON adds the coverage percentage to the counts.
+func (c Counts) MarshalJSON() ([]byte, error) {
+	type counts Counts
+	return json.Marshal(struct {
+		counts
+		Percent float64 `json:"percent"`
+	}{counts(c), c.Percent()})
+}
+
+// New returns the documentation coverage of the loaded packages. Fields are counted as they are
+// printed, so hidden fields are not counted.
+func New(packages []loader.Package) Report {
+	var report Report
+	for _, pkg := range packages {
+		pkgReport := PackageReport{Path: pkg.Path}
+		for _, t := range pkg.Types {
+			typeReport := TypeReport{Name: t.Name, Coverage: Counts{Types: 1}}
+			if t.Doc != "" {
+				typeReport.Coverage.DocumentedTypes = 1
+			}
+			for _, field := range t.Fields {
+				if field.Hidden {
+					continue
+				}
+				typeReport.Coverage.Fields++
+				if field.Doc != "" {
+					typeReport.Coverage.DocumentedFields++
+				} else {
+					typeReport.UndocumentedFields = append(typeReport.UndocumentedFields, field.JSONProperty)
+				}
+			}
+			pkgReport.Types = append(pkgReport.Types, typeReport)
+			pkgReport.Coverage.add(typeReport.Coverage)
+		}
+		report.Packages = append(report.Packages, pkgReport)
+		report.Total.add(pkgReport.Coverage)
+	}
+	return report
+}
+
+// Check returns an error if the coverage of the report is below minimum percent.
+func (r Report) Check(minimum float64) error {
+	if percent := r.Total.Percent(); percent < minimum {
+		return errors.Errorf("documentation coverage %.1f%% is below the minimum %.1f%%", percent, minimum)
+	}
+	return nil
+}
+
+// WriteJSON writes the report to w as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(r), "failed to write coverage report")
+}
+
+// WriteTable writes the report to w as a text table with a row per package and type, followed by
+// the undocumented fields of each type and a total.
+func (r Report) WriteTable(w io.Writer) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE/TYPE\tTYPES\tFIELDS\tCOVERAGE\tUNDOCUMENTED")
+	for _, pkg := range r.Packages {
+		writeRow(tw, pkg.Path, pkg.Coverage, "")
+		for _, t := range pkg.Types {
+			writeRow(tw, "  "+t.Name, t.Coverage, strings.Join(t.UndocumentedFields, ", "))
+		}
+	}
+	writeRow(tw, "TOTAL", r.Total, "")
+	if err := tw.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write coverage report")
+	}
+
+	// Rows without undocumented fields are padded up to the empty last column.
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+	return nil
+}
+
+func writeRow(w io.Writer, name string, c Counts, undocumented string) {
+	fmt.Fprintf(w, "%s\t%d/%d\t%d/%d\t%.1f%%\t%s\n",
+		name, c.DocumentedTypes, c.Types, c.DocumentedFields, c.Fields, c.Percent(), undocumented)
+}
@@ -0,0 +1,65 @@
+package coverage_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/coverage"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+var _ = Describe("Coverage", func() {
+	var report coverage.Report
+
+	BeforeEach(func() {
+		packages, err := loader.New([]string{"github.com/jimmidyson/prettyconf/pkg/coverage/testdata"}, logger).Load()
+		Expect(err).NotTo(HaveOccurred())
+		report = coverage.New(packages)
+	})
+
+	It("counts documented types and printed fields", func() {
+		Expect(report.Total).To(Equal(coverage.Counts{Types: 2, DocumentedTypes: 1, Fields: 4, DocumentedFields: 2}))
+		Expect(report.Total.Percent()).To(BeNumerically("==", 50))
+		Expect(report.Packages[0].Types[0].UndocumentedFields).To(Equal([]string{"port", "nested"}))
+	})
+
+	It("fails below the minimum", func() {
+		Expect(report.Check(50)).To(Succeed())
+		Expect(report.Check(75)).To(MatchError("documentation coverage 50.0% is below the minimum 75.0%"))
+	})
+
+	It("writes a table", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteTable(&buf)).To(Succeed())
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "coverage.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal(string(expected)))
+	})
+
+	It("writes JSON", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteJSON(&buf)).To(Succeed())
+		Expect(buf.String()).To(MatchJSON(`{
+  "total": {"types": 2, "documentedTypes": 1, "fields": 4, "documentedFields": 2, "percent": 50},
+  "packages": [{
+    "path": "github.com/jimmidyson/prettyconf/pkg/coverage/testdata",
+    "coverage": {"types": 2, "documentedTypes": 1, "fields": 4, "documentedFields": 2, "percent": 50},
+    "types": [
+      {
+        "name": "Config",
+        "coverage": {"types": 1, "documentedTypes": 1, "fields": 3, "documentedFields": 1, "percent": 50},
+        "undocumentedFields": ["port", "nested"]
+      },
+      {
+        "name": "Nested",
+        "coverage": {"types": 1, "documentedTypes": 0, "fields": 1, "documentedFields": 1, "percent": 50}
+      }
+    ]
+  }]
+}`))
+	})
+})
@@ -0,0 +1,16 @@
+package testdata
+
+// Config is documented.
+type Config struct {
+	// Name is documented.
+	Name string `json:"name"`
+	Port int    `json:"port"`
+	// Internal is hidden so is not counted.
+	Internal string `json:"internal" prettyconf:"hidden"`
+	Nested   Nested `json:"nested"`
+}
+
+type Nested struct {
+	// Value is documented.
+	Value string `json:"value"`
+}
@@ -0,0 +1,150 @@
+// Package doccomment renders Go doc comments, including headings, lists, code blocks and doc links,
+// for the different outputs generated from config types.
+package doccomment
+
+import (
+	"go/doc/comment"
+	"strings"
+)
+
+// Renderer parses Go doc comments and renders them as text, Markdown or HTML, rewriting doc links
+// to types and fields into links to config key paths.
+type Renderer struct {
+	// KeyPaths maps type names qualified by their package path, e.g. `example.com/app.Config`, and
+	// field names qualified by those, e.g. `example.com/app.Config.Listen`, to the config key paths
+	// they are found at. Doc links to names not in KeyPaths are rendered as their text.
+	KeyPaths map[string]string
+	// Package is the path of the package that rendered docs are from, which doc links without an
+	// import path refer to. See In.
+	Package string
+	// TextWidth is the maximum width of text output lines. If zero it defaults to 80. If negative
+	// there is no limit.
+	TextWidth int
+}
+
+// In returns a copy of the renderer for docs from the package with path pkgPath.
+func (r *Renderer) In(pkgPath string) *Renderer {
+	in := *r
+	in.Package = pkgPath
+	return &in
+}
+
+// Text renders doc as plain text, reflowing paragraphs and replacing doc links with key paths.
+func (r *Renderer) Text(doc string) string {
+	if strings.TrimSpace(doc) == "" {
+		return ""
+	}
+	d := r.parse(doc)
+	rewriteDocLinks(d, func(link *comment.DocLink) comment.Text {
+		if keyPath, ok := r.keyPath(link); ok {
+			return comment.Plain(keyPath)
+		}
+		return comment.Plain(plainText(link.Text))
+	})
+	p := &comment.Printer{TextWidth: r.TextWidth, TextCodePrefix: "    "}
+	if p.TextWidth == 0 {
+		p.TextWidth = 80
+	}
+	return strings.TrimRight(string(p.Text(d)), "\n")
+}
+
+// Markdown renders doc as Markdown, linking doc links to the anchors of their key paths.
+func (r *Renderer) Markdown(doc string) string {
+	return strings.TrimRight(string(r.printer().Markdown(r.parse(doc))), "\n")
+}
+
+// HTML renders doc as HTML, linking doc links to the anchors of their key paths.
+func (r *Renderer) HTML(doc string) string {
+	return strings.TrimRight(string(r.printer().HTML(r.parse(doc))), "\n")
+}
+
+// Anchor returns the anchor used to link to a key path in Markdown and HTML output.
+func Anchor(keyPath string) string {
+	return strings.NewReplacer(".", "-", "[", "", "]", "", "*", "").Replace(keyPath)
+}
+
+func (r *Renderer) parse(doc string) *comment.Doc {
+	p := &comment.Parser{
+		LookupSym: func(recv, name string) bool {
+			_, ok := r.keyPath(&comment.DocLink{Recv: recv, Name: name})
+			return ok
+		},
+	}
+	return p.Parse(doc)
+}
+
+func (r *Renderer) printer() *comment.Printer {
+	return &comment.Printer{
+		HeadingID: func(h *comment.Heading) string {
+			return ""
+		},
+		DocLinkURL: func(link *comment.DocLink) string {
+			if keyPath, ok := r.keyPath(link); ok {
+				return "#" + Anchor(keyPath)
+			}
+			return link.DefaultURL("https://pkg.go.dev")
+		},
+	}
+}
+
+func (r *Renderer) keyPath(link *comment.DocLink) (string, bool) {
+	pkgPath := link.ImportPath
+	if pkgPath == "" {
+		pkgPath = r.Package
+	}
+	name := link.Name
+	if link.Recv != "" {
+		name = link.Recv + "." + name
+	}
+	keyPath, ok := r.KeyPaths[pkgPath+"."+name]
+	return keyPath, ok
+}
+
+// rewriteDocLinks replaces every doc link in d with the text returned by rewrite.
+func rewriteDocLinks(d *comment.Doc, rewrite func(*comment.DocLink) comment.Text) {
+	var rewriteBlocks func(blocks []comment.Block)
+	rewriteBlocks = func(blocks []comment.Block) {
+		for _, block := range blocks {
+			switch b := block.(type) {
+			case *comment.Paragraph:
+				b.Text = rewriteText(b.Text, rewrite)
+			case *comment.Heading:
+				b.Text = rewriteText(b.Text, rewrite)
+			case *comment.List:
+				for _, item := range b.Items {
+					rewriteBlocks(item.Content)
+				}
+			}
+		}
+	}
+	rewriteBlocks(d.Content)
+}
+
+func rewriteText(texts []comment.Text, rewrite func(*comment.DocLink) comment.Text) []comment.Text {
+	for i, text := range texts {
+		switch t := text.(type) {
+		case *comment.DocLink:
+			texts[i] = rewrite(t)
+		case *comment.Link:
+			t.Text = rewriteText(t.Text, rewrite)
+		}
+	}
+	return texts
+}
+
+func plainText(texts []comment.Text) string {
+	var sb strings.Builder
+	for _, text := range texts {
+		switch t := text.(type) {
+		case comment.Plain:
+			sb.WriteString(string(t))
+		case comment.Italic:
+			sb.WriteString(string(t))
+		case *comment.Link:
+			sb.WriteString(plainText(t.Text))
+		case *comment.DocLink:
+			sb.WriteString(plainText(t.Text))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package doccomment_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
+)
+
+const doc = `Server configures the
+server, see [Server.Address] and [io.Reader].
+
+# Options
+
+  - fast
+  - slow
+
+For example:
+
+	address: localhost
+`
+
+var _ = Describe("Renderer", func() {
+	var renderer *doccomment.Renderer
+
+	BeforeEach(func() {
+		renderer = &doccomment.Renderer{Package: "example.com/app", KeyPaths: map[string]string{
+			"example.com/app.Server":         "server",
+			"example.com/app.Server.Address": "server.address",
+			"example.com/admin.Server":       "admin.server",
+		}}
+	})
+
+	It("resolves doc links in the package of the doc", func() {
+		Expect(renderer.In("example.com/admin").Text("See [Server].")).To(Equal("See admin.server."))
+		Expect(renderer.In("example.com/other").Text("See [Server].")).To(Equal("See [Server]."))
+		Expect(renderer.Package).To(Equal("example.com/app"))
+	})
+
+	It("renders empty docs", func() {
+		Expect(renderer.Text("")).To(BeEmpty())
+	})
+
+	It("renders text with key paths", func() {
+		Expect(renderer.Text(doc)).To(Equal(`Server configures the server, see server.address and io.Reader.
+
+# Options
+
+  - fast
+  - slow
+
+For example:
+
+    address: localhost`))
+	})
+
+	It("reflows text to the width", func() {
+		renderer.TextWidth = 20
+		Expect(renderer.Text("One two three four five six seven.")).To(Equal("One two three four\nfive six seven."))
+	})
+
+	It("renders markdown with anchors", func() {
+		Expect(renderer.Markdown(doc)).To(Equal(`Server configures the server, see [Server.Address](#server-address) and [io.Reader](https://pkg.go.dev/io#Reader).
+
+### Options
+
+  - fast
+  - slow
+
+For example:
+
+	address: localhost`))
+	})
+
+	It("renders html with anchors", func() {
+		Expect(renderer.HTML(doc)).To(ContainSubstring(`<a href="#server-address">Server.Address</a>`))
+		Expect(renderer.HTML(doc)).To(ContainSubstring(`<h3>Options</h3>`))
+		Expect(renderer.HTML(doc)).To(ContainSubstring(`<li>fast`))
+	})
+})
@@ -0,0 +1,13 @@
+package doccomment_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDocComment(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Doc Comment Suite")
+}
@@ -0,0 +1,214 @@
+package env
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// Lookup looks up the value of an environment variable, like os.LookupEnv.
+type Lookup func(name string) (string, bool)
+
+// VarError is an error setting a field from an environment variable.
+type VarError struct {
+	Var   Var
+	Value string
+	Err   error
+}
+
+func (e *VarError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s (%s): %v", e.Value, e.Var.Name, e.Var.Path, e.Err)
+}
+
+// Errors holds all of the errors from applying environment variables.
+type Errors []*VarError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ApplyEnv loads the type of the config that conf points to and sets its fields from the
+// environment variables of the process named with prefix. See Vars for how variables are named.
+func ApplyEnv(conf interface{}, prefix string, logger logr.Logger) error {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return err
+	}
+	return Apply(conf, Vars(rootType, packages, prefix), os.LookupEnv)
+}
+
+// Apply sets the fields of the config that conf points to from the variables in vars that are
+// found by lookup, converting values to the field types. Lists and maps are written as comma
+// separated values, with map entries written as `key=value`. Nil pointers on the way to a field
+// are allocated. If any values cannot be converted an Errors is returned after all other
+// variables have been applied.
+func Apply(conf interface{}, vars []Var, lookup Lookup) error {
+	confValue := reflect.ValueOf(conf)
+	if confValue.Kind() != reflect.Ptr || confValue.IsNil() {
+		return errors.Errorf("conf must be a non-nil pointer, got %T", conf)
+	}
+
+	var errs Errors
+	for _, v := range vars {
+		value, ok := lookup(v.Name)
+		if !ok {
+			continue
+		}
+		if err := v.set(confValue, value); err != nil {
+			errs = append(errs, &VarError{Var: v, Value: value, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Set sets the field of v in the config that conf points to from value, converted as by Apply.
+func (v Var) Set(conf interface{}, value string) error {
+	confValue := reflect.ValueOf(conf)
+	if confValue.Kind() != reflect.Ptr || confValue.IsNil() {
+		return errors.Errorf("conf must be a non-nil pointer, got %T", conf)
+	}
+	return v.set(confValue, value)
+}
+
+func (v Var) set(confValue reflect.Value, value string) error {
+	field, err := fieldValue(confValue, v.FieldPath, true)
+	if err != nil {
+		return err
+	}
+	return setValue(field, value)
+}
+
+// fieldValue returns the value of the field at the Go field path from value. If allocate is true nil
+// pointers on the way are allocated, otherwise an invalid value is returned for them.
+func fieldValue(value reflect.Value, fieldPath []string, allocate bool) (reflect.Value, error) {
+	for _, name := range fieldPath {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !allocate {
+					return reflect.Value{}, nil
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, errors.Errorf("cannot find field %s in %s", name, value.Type())
+		}
+		value = value.FieldByName(name)
+		if !value.IsValid() {
+			return reflect.Value{}, errors.Errorf("no field %s", name)
+		}
+	}
+	return value, nil
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func setValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setValue(field.Elem(), value)
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		items := splitList(value)
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), item); err != nil {
+				return errors.Wrapf(err, "item %d", i)
+			}
+		}
+		field.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(field.Type())
+		for _, entry := range splitList(value) {
+			idx := strings.Index(entry, "=")
+			if idx < 0 {
+				return errors.Errorf("map entry %q is not key=value", entry)
+			}
+			k := reflect.New(field.Type().Key()).Elem()
+			if err := setValue(k, strings.TrimSpace(entry[:idx])); err != nil {
+				return errors.Wrapf(err, "map key %q", entry[:idx])
+			}
+			v := reflect.New(field.Type().Elem()).Elem()
+			if err := setValue(v, strings.TrimSpace(entry[idx+1:])); err != nil {
+				return errors.Wrapf(err, "map value for %q", entry[:idx])
+			}
+			m.SetMapIndex(k, v)
+		}
+		field.Set(m)
+	default:
+		return errors.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
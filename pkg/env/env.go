@@ -0,0 +1,159 @@
+// Package env maps config fields to environment variables, generates documented .env templates
+// and applies environment variable overrides onto config values.
+package env
+
+import (
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// Var is an environment variable that sets a scalar config field.
+type Var struct {
+	// Name is the name of the environment variable.
+	Name string
+	// Path is the dot separated JSON key path of the field.
+	Path string
+	// FieldPath holds the Go field names leading to the field from the root type.
+	FieldPath []string
+	// Field is the loaded field the variable sets.
+	Field loader.Field
+	// Owner is the type that declares the field.
+	Owner loader.Type
+}
+
+// Option configures the variables of a config.
+type Option func(*options)
+
+type options struct {
+	order loader.FieldOrder
+}
+
+func newOptions(opts []Option) options {
+	o := options{order: loader.ExplicitOrder}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithFieldOrder sets the strategy used to order the variables of the fields of each type. The
+// default is loader.ExplicitOrder.
+func WithFieldOrder(order loader.FieldOrder) Option {
+	return func(o *options) {
+		o.order = order
+	}
+}
+
+// Vars returns the environment variables for every scalar leaf field reachable from rootType
+// through nested structs, with the fields of each type in the order set by WithFieldOrder. Hidden
+// fields are included. A variable is named after its prefix and the JSON key path of its field,
+// e.g. `APP_SERVER_LISTEN_ADDRESS` for `server.listenAddress` with prefix `APP`, unless the field
+// sets the name with the `env=NAME` prettyconf tag option. Slices and maps of scalars are included;
+// slices of structs and interface fields are not.
+func Vars(rootType loader.Type, packages []loader.Package, prefix string, opts ...Option) []Var {
+	o := newOptions(opts)
+	sortFields := func(fields []loader.Field) []loader.Field {
+		return loader.SortFields(fields, o.order)
+	}
+	var vars []Var
+	loader.Walk(rootType, packages, sortFields, func(field *loader.WalkedField) bool {
+		if field.JSONProperty == "" {
+			return false
+		}
+		if field.Elem != nil {
+			// Only nested structs are walked, not slices or maps of them.
+			return field.ElemPath == field.Path
+		}
+		if !IsSettable(field.Type) {
+			return false
+		}
+
+		var nameParts, fieldPath []string
+		for f := field; f != nil; f = f.Parent {
+			nameParts = append([]string{envSegment(f.JSONProperty)}, nameParts...)
+			fieldPath = append([]string{f.Name}, fieldPath...)
+		}
+		if prefix != "" {
+			nameParts = append([]string{envSegment(prefix)}, nameParts...)
+		}
+		name := field.Env
+		if name == "" {
+			name = strings.Join(nameParts, "_")
+		}
+		vars = append(vars, Var{Name: name, Path: field.Path, FieldPath: fieldPath, Field: field.Field, Owner: field.Owner})
+		return false
+	})
+	return vars
+}
+
+// IsSettable returns true if a field of type t can be set from a single string: booleans, numbers
+// other than complex numbers, strings, named types of those such as time.Duration, and slices and
+// maps of those.
+func IsSettable(t types.Type) bool {
+	switch typ := t.(type) {
+	case *types.Pointer:
+		return IsSettable(typ.Elem())
+	case *types.Named:
+		return IsSettable(typ.Underlying())
+	case *types.Basic:
+		return isSettableBasic(typ)
+	case *types.Slice:
+		elem, ok := typ.Elem().Underlying().(*types.Basic)
+		return ok && isSettableBasic(elem)
+	case *types.Map:
+		key, keyOK := typ.Key().Underlying().(*types.Basic)
+		elem, elemOK := typ.Elem().Underlying().(*types.Basic)
+		return keyOK && elemOK && isSettableBasic(key) && isSettableBasic(elem)
+	default:
+		return false
+	}
+}
+
+// isSettableBasic returns true for the basic types that setValue parses.
+func isSettableBasic(t *types.Basic) bool {
+	if t.Kind() == types.Uintptr {
+		return false
+	}
+	return t.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0
+}
+
+// envSegment converts a JSON property such as `listenAddress` or `listen-address` to an environment
+// variable name segment such as `LISTEN_ADDRESS`.
+func envSegment(property string) string {
+	return strings.ToUpper(strings.Join(SplitWords(property), "_"))
+}
+
+// SplitWords splits a JSON property into its words, e.g. `listenAddress` and `listen-address` into
+// `listen` and `address`, or `HTTPPort` into `HTTP` and `Port`. Words start at upper case letters
+// that follow a lower case letter or digit, or that start a word after an acronym. Runes other than
+// letters and digits separate words.
+func SplitWords(property string) []string {
+	var words []string
+	var word []rune
+	endWord := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	runes := []rune(property)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				endWord()
+			}
+			word = append(word, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			endWord()
+		}
+	}
+	endWord()
+	return words
+}
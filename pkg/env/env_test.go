@@ -0,0 +1,178 @@
+package env_test
+
+import (
+	"bytes"
+	"go/types"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/env"
+	"github.com/jimmidyson/prettyconf/pkg/env/testdata"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+func lookupFrom(vars map[string]string) env.Lookup {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+var _ = Describe("Env", func() {
+	var vars []env.Var
+
+	BeforeEach(func() {
+		rootType, packages, err := loader.LoadFor(testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		vars = env.Vars(rootType, packages, "app")
+	})
+
+	It("names variables after their key paths", func() {
+		names := map[string]string{}
+		for _, v := range vars {
+			names[v.Path] = v.Name
+		}
+		Expect(names).To(Equal(map[string]string{
+			"server.listenAddress":  "APP_SERVER_LISTEN_ADDRESS",
+			"server.port":           "APP_SERVER_PORT",
+			"server.timeout":        "APP_SERVER_TIMEOUT",
+			"server.allowedOrigins": "APP_SERVER_ALLOWED_ORIGINS",
+			"server.labels":         "APP_SERVER_LABELS",
+			"server.workers":        "APP_SERVER_WORKERS",
+			"database.url":          "DATABASE_URL",
+			"database.password":     "APP_DATABASE_PASSWORD",
+			"database.poolSize":     "APP_DATABASE_POOL_SIZE",
+			"debug":                 "APP_DEBUG",
+		}))
+	})
+
+	It("orders variables by the fields of each type", func() {
+		pathsOf := func(vars []env.Var) []string {
+			paths := make([]string, 0, len(vars))
+			for _, v := range vars {
+				paths = append(paths, v.Path)
+			}
+			return paths
+		}
+		Expect(pathsOf(vars)).To(Equal([]string{
+			"server.listenAddress", "server.port", "server.timeout", "server.allowedOrigins", "server.labels", "server.workers",
+			"debug",
+			"database.url", "database.password", "database.poolSize",
+		}))
+
+		rootType, packages, err := loader.LoadFor(testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pathsOf(env.Vars(rootType, packages, "app", env.WithFieldOrder(loader.AlphabeticalOrder)))).To(Equal([]string{
+			"database.password", "database.poolSize", "database.url",
+			"debug",
+			"server.allowedOrigins", "server.labels", "server.listenAddress", "server.port", "server.timeout", "server.workers",
+		}))
+	})
+
+	It("applies variables to a config", func() {
+		conf := testdata.Config{}
+		Expect(env.Apply(&conf, vars, lookupFrom(map[string]string{
+			"APP_SERVER_LISTEN_ADDRESS":  "0.0.0.0",
+			"APP_SERVER_PORT":            "8080",
+			"APP_SERVER_TIMEOUT":         "30s",
+			"APP_SERVER_ALLOWED_ORIGINS": "a.example.com, b.example.com",
+			"APP_SERVER_LABELS":          "team=core,tier=web",
+			"DATABASE_URL":               "postgres://db",
+			"APP_DATABASE_POOL_SIZE":     "10",
+			"APP_DEBUG":                  "true",
+		}))).To(Succeed())
+		Expect(conf).To(Equal(testdata.Config{
+			Server: testdata.ServerConfig{
+				ListenAddress:  "0.0.0.0",
+				Port:           8080,
+				Timeout:        30 * time.Second,
+				AllowedOrigins: []string{"a.example.com", "b.example.com"},
+				Labels:         map[string]string{"team": "core", "tier": "web"},
+			},
+			Database: &testdata.DatabaseConfig{URL: "postgres://db", PoolSize: 10},
+			Debug:    true,
+		}))
+	})
+
+	It("leaves nil pointers unset when no variables are set", func() {
+		conf := testdata.Config{}
+		Expect(env.Apply(&conf, vars, lookupFrom(nil))).To(Succeed())
+		Expect(conf.Database).To(BeNil())
+	})
+
+	It("reports every invalid value", func() {
+		conf := testdata.Config{}
+		err := env.Apply(&conf, vars, lookupFrom(map[string]string{
+			"APP_SERVER_PORT":        "http",
+			"APP_DATABASE_POOL_SIZE": "1000",
+			"APP_DEBUG":              "true",
+		}))
+		Expect(err).To(HaveOccurred())
+		Expect(err.(env.Errors)).To(HaveLen(2))
+		Expect(err.Error()).To(ContainSubstring(`invalid value "http" for APP_SERVER_PORT (server.port)`))
+		Expect(err.Error()).To(ContainSubstring(`invalid value "1000" for APP_DATABASE_POOL_SIZE (database.poolSize)`))
+		Expect(conf.Debug).To(BeTrue())
+	})
+
+	It("writes a documented template", func() {
+		conf := testdata.Config{
+			Server: testdata.ServerConfig{
+				ListenAddress: "localhost",
+				Port:          8080,
+				Timeout:       time.Minute,
+				Labels:        map[string]string{"tier": "web", "team": "core"},
+			},
+			Database: &testdata.DatabaseConfig{URL: "postgres://db", Password: "hunter2"},
+		}
+		var buf bytes.Buffer
+		rootType, packages, err := loader.LoadFor(testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(env.WriteTemplate(&buf, conf, rootType, packages, "app")).To(Succeed())
+		expected, err := os.ReadFile(filepath.Join("testdata", "template.env"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal(string(expected)))
+	})
+
+	It("single quotes values that shells would interpret", func() {
+		conf := testdata.Config{Server: testdata.ServerConfig{ListenAddress: `it's "$HOST"`}}
+		var buf bytes.Buffer
+		rootType, packages, err := loader.LoadFor(testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(env.WriteTemplate(&buf, conf, rootType, packages, "app")).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("\nAPP_SERVER_LISTEN_ADDRESS='it'\\''s \"$HOST\"'\n"))
+	})
+})
+
+var _ = Describe("IsSettable", func() {
+	DescribeTable("types",
+		func(t types.Type, expected bool) {
+			Expect(env.IsSettable(t)).To(Equal(expected))
+		},
+		Entry("string", types.Typ[types.String], true),
+		Entry("pointer to float", types.NewPointer(types.Typ[types.Float64]), true),
+		Entry("complex", types.Typ[types.Complex128], false),
+		Entry("uintptr", types.Typ[types.Uintptr], false),
+		Entry("list of int", types.NewSlice(types.Typ[types.Int]), true),
+		Entry("list of complex", types.NewSlice(types.Typ[types.Complex64]), false),
+		Entry("map of string to complex", types.NewMap(types.Typ[types.String], types.Typ[types.Complex64]), false),
+		Entry("struct", types.NewStruct(nil, nil), false),
+	)
+})
+
+var _ = Describe("SplitWords", func() {
+	DescribeTable("words",
+		func(property string, expected []string) {
+			Expect(env.SplitWords(property)).To(Equal(expected))
+		},
+		Entry("camel case", "listenAddress", []string{"listen", "Address"}),
+		Entry("kebab case", "listen-address", []string{"listen", "address"}),
+		Entry("acronym", "HTTPPort", []string{"HTTP", "Port"}),
+		Entry("digits", "http2Workers", []string{"http2", "Workers"}),
+		Entry("separators", "_a..b_", []string{"a", "b"}),
+	)
+})
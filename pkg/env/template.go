@@ -0,0 +1,123 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+// PrintTemplate loads the type of conf and writes a documented .env template for it to w. See
+// WriteTemplate.
+func PrintTemplate(conf interface{}, prefix string, w io.Writer, logger logr.Logger, opts ...Option) error {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return err
+	}
+	return WriteTemplate(w, conf, rootType, packages, prefix, opts...)
+}
+
+// WriteTemplate writes a .env template to w with an assignment for each of the variables of
+// rootType named with prefix and ordered by opts, as returned by Vars, set to the value of its field
+// in conf and preceded by the field's doc and type. Hidden fields are left out and the values of
+// sensitive fields are left empty.
+func WriteTemplate(w io.Writer, conf interface{}, rootType loader.Type, packages []loader.Package, prefix string, opts ...Option) error {
+	// Docs are wrapped so that comment lines fit in 80 columns.
+	renderer := &doccomment.Renderer{KeyPaths: printer.KeyPaths(rootType, packages), TextWidth: 78}
+	confValue := reflect.ValueOf(conf)
+	first := true
+	for _, v := range Vars(rootType, packages, prefix, opts...) {
+		if v.Field.Hidden {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(w)
+		}
+		first = false
+
+		fmt.Fprintf(w, "# %s\n", v.Path)
+		if doc := renderer.In(v.Owner.Package).Text(printer.FieldDoc(v.Field)); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				fmt.Fprintln(w, strings.TrimRight("# "+line, " "))
+			}
+		}
+		fmt.Fprintf(w, "# %s\n", printer.TypeAnnotation(v.Field))
+
+		value := ""
+		if !v.Field.Sensitive {
+			var err error
+			value, err = v.value(confValue)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get value of %s", v.Path)
+			}
+		}
+		fmt.Fprintf(w, "%s=%s\n", v.Name, quoteValue(value))
+	}
+	return nil
+}
+
+// Value returns the value of the field of v in conf formatted as it would be in an environment
+// variable, or an empty string if a pointer on the way to the field is nil.
+func (v Var) Value(conf interface{}) (string, error) {
+	return v.value(reflect.ValueOf(conf))
+}
+
+func (v Var) value(confValue reflect.Value) (string, error) {
+	field, err := fieldValue(confValue, v.FieldPath, false)
+	if err != nil {
+		return "", err
+	}
+	return formatValue(field), nil
+}
+
+func formatValue(value reflect.Value) string {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return ""
+	}
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, formatValue(value.Index(i)))
+		}
+		return strings.Join(items, ",")
+	case reflect.Map:
+		entries := make([]string, 0, value.Len())
+		for _, k := range value.MapKeys() {
+			entries = append(entries, formatValue(k)+"="+formatValue(value.MapIndex(k)))
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, ",")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// quoteValue single quotes value if it contains whitespace or characters that shells and .env
+// loaders interpret. As in POSIX shells, a single quote in value ends the quoted string, is written
+// escaped with a backslash and starts a new quoted string.
+func quoteValue(value string) string {
+	if strings.ContainsAny(value, " \t\n#\"'\\$`") {
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	}
+	return value
+}
@@ -0,0 +1,48 @@
+package testdata
+
+import "time"
+
+// Config is an application config.
+type Config struct {
+	// Server configures the server.
+	Server ServerConfig `json:"server"`
+	// Database configures the database.
+	Database *DatabaseConfig `json:"database,omitempty" prettyconf:"order=1"`
+	// Debug enables debug logging.
+	Debug bool `json:"debug,omitempty"`
+	// Plugins configure plugins, which cannot be set from the environment.
+	Plugins []PluginConfig `json:"plugins,omitempty"`
+}
+
+// ServerConfig configures the server.
+type ServerConfig struct {
+	// ListenAddress is the address to listen on.
+	ListenAddress string `json:"listenAddress"`
+	// Port is the port to listen on. It is ignored if [ServerConfig.ListenAddress] includes a
+	// port.
+	Port int `json:"port"`
+	// Timeout is the request timeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// AllowedOrigins are the allowed CORS origins.
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// Labels are added to every response.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Workers is the number of request workers, which is not documented.
+	Workers int `json:"workers,omitempty" prettyconf:"hidden"`
+}
+
+// DatabaseConfig configures the database.
+type DatabaseConfig struct {
+	// URL is the database URL.
+	URL string `json:"url" prettyconf:"env=DATABASE_URL"`
+	// Password is the database password.
+	Password string `json:"password" prettyconf:"secret"`
+	// PoolSize is the size of the connection pool.
+	PoolSize uint8 `json:"poolSize,omitempty"`
+}
+
+// PluginConfig configures a plugin.
+type PluginConfig struct {
+	// Name is the plugin name.
+	Name string `json:"name"`
+}
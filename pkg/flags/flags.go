@@ -0,0 +1,164 @@
+// Package flags registers command-line flags for the fields of a config, so that every scalar config
+// field can also be set on the command line.
+package flags
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
+	"github.com/jimmidyson/prettyconf/pkg/env"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+// Option configures the flags registered for a config.
+type Option func(*options)
+
+type options struct {
+	order loader.FieldOrder
+}
+
+// WithFieldOrder sets the strategy used to order the flags of the fields of each type in the help
+// output. The default is loader.ExplicitOrder.
+func WithFieldOrder(order loader.FieldOrder) Option {
+	return func(o *options) {
+		o.order = order
+	}
+}
+
+// RegisterFor loads the type of the config that conf points to and registers flags for its fields on
+// fs. See Register.
+func RegisterFor(fs *flag.FlagSet, conf interface{}, logger logr.Logger, opts ...Option) error {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return err
+	}
+	return Register(fs, conf, rootType, packages, opts...)
+}
+
+// Register registers a flag on fs for every scalar leaf field of the config that conf points to,
+// using the loaded rootType and packages. Flags are named after the JSON key path of their field,
+// e.g. `server.listen-address` for `server.listenAddress`, have the field doc as their usage and
+// the current value of the field as their default. Parsing fs sets the fields of conf, allocating
+// nil pointers on the way to a field only when its flag is set. Lists and maps are written as comma
+// separated values, with map entries written as `key=value`. The defaults of sensitive fields are
+// not shown. fs.Usage is set to print the flags as PrintDefaults does, which lists the flags of the
+// config in the order set by WithFieldOrder and leaves out the flags of hidden fields. Flags of
+// hidden fields have no usage.
+func Register(fs *flag.FlagSet, conf interface{}, rootType loader.Type, packages []loader.Package, opts ...Option) error {
+	if v := reflect.ValueOf(conf); v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.Errorf("conf must be a non-nil pointer, got %T", conf)
+	}
+	o := options{order: loader.ExplicitOrder}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	renderer := &doccomment.Renderer{KeyPaths: printer.KeyPaths(rootType, packages), TextWidth: -1}
+	for i, v := range env.Vars(rootType, packages, "", env.WithFieldOrder(o.order)) {
+		name := Name(v.Path)
+		if fs.Lookup(name) != nil {
+			return errors.Errorf("flag %s for %s is already defined", name, v.Path)
+		}
+		fs.Var(&fieldValue{conf: conf, v: v, index: i}, name, usage(renderer, v))
+	}
+	fs.Usage = func() {
+		if fs.Name() == "" {
+			fmt.Fprintf(fs.Output(), "Usage:\n")
+		} else {
+			fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+		}
+		PrintDefaults(fs)
+	}
+	return nil
+}
+
+// PrintDefaults prints the flags of fs and their defaults to the output of fs, as
+// flag.FlagSet.PrintDefaults does, leaving out the flags of hidden fields. Flags registered by
+// Register are listed in the order of their fields, after any other flags.
+func PrintDefaults(fs *flag.FlagSet) {
+	var printed []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		if value, ok := f.Value.(*fieldValue); !ok || !value.v.Field.Hidden {
+			printed = append(printed, f)
+		}
+	})
+	sort.SliceStable(printed, func(i, j int) bool {
+		vi, iField := printed[i].Value.(*fieldValue)
+		vj, jField := printed[j].Value.(*fieldValue)
+		if iField && jField {
+			return vi.index < vj.index
+		}
+		return !iField && jField
+	})
+	// Each flag is printed from its own flag set to keep the order and the flag package's format.
+	for _, f := range printed {
+		single := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+		single.SetOutput(fs.Output())
+		single.Var(f.Value, f.Name, f.Usage)
+		single.Lookup(f.Name).DefValue = f.DefValue
+		single.PrintDefaults()
+	}
+}
+
+// Name returns the flag name for the JSON key path path, with each key converted to kebab case.
+func Name(path string) string {
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		keys[i] = kebabCase(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+func kebabCase(key string) string {
+	return strings.ToLower(strings.Join(env.SplitWords(key), "-"))
+}
+
+// usage returns the doc of the field of v on a single line, or nothing for hidden fields.
+func usage(renderer *doccomment.Renderer, v env.Var) string {
+	if v.Field.Hidden {
+		return ""
+	}
+	return strings.Join(strings.Fields(renderer.In(v.Owner.Package).Text(printer.FieldDoc(v.Field))), " ")
+}
+
+// fieldValue is a flag.Value that gets and sets a config field.
+type fieldValue struct {
+	conf interface{}
+	v    env.Var
+	// index is the position of the field among the flags registered for the config.
+	index int
+}
+
+func (f *fieldValue) String() string {
+	// The flag package calls String on a zero fieldValue to check for zero defaults.
+	if f == nil || f.conf == nil || f.v.Field.Sensitive {
+		return ""
+	}
+	value, err := f.v.Value(f.conf)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func (f *fieldValue) Set(value string) error {
+	return f.v.Set(f.conf, value)
+}
+
+// IsBoolFlag allows boolean flags to be set without a value, e.g. `-debug`.
+func (f *fieldValue) IsBoolFlag() bool {
+	t := f.v.Field.Type
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
@@ -0,0 +1,122 @@
+package flags_test
+
+import (
+	"bytes"
+	"flag"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/flags"
+	"github.com/jimmidyson/prettyconf/pkg/flags/testdata"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+var _ = Describe("Flags", func() {
+	var (
+		conf testdata.Config
+		fs   *flag.FlagSet
+	)
+
+	BeforeEach(func() {
+		conf = testdata.Config{
+			Server: testdata.ServerConfig{ListenAddress: "localhost", Port: 8080},
+		}
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(GinkgoWriter)
+		Expect(flags.RegisterFor(fs, &conf, logger)).To(Succeed())
+	})
+
+	It("names flags after key paths", func() {
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+		Expect(names).To(ConsistOf(
+			"server.listen-address",
+			"server.port",
+			"server.timeout",
+			"server.allowed-origins",
+			"server.http2-workers",
+			"database.url",
+			"database.password",
+			"debug",
+		))
+	})
+
+	It("uses field docs as usage and current values as defaults", func() {
+		Expect(fs.Lookup("server.port").Usage).To(Equal("port is the port to listen on. It is ignored if server.listenAddress includes a port."))
+		Expect(fs.Lookup("server.port").DefValue).To(Equal("8080"))
+		Expect(fs.Lookup("database.password").Usage).To(Equal("password is the database password."))
+
+		var buf bytes.Buffer
+		fs.SetOutput(&buf)
+		fs.PrintDefaults()
+		Expect(buf.String()).To(ContainSubstring("listenAddress is the address to listen on. (default localhost)"))
+	})
+
+	It("does not document hidden fields", func() {
+		Expect(fs.Lookup("server.http2-workers").Usage).To(BeEmpty())
+
+		var buf bytes.Buffer
+		fs.SetOutput(&buf)
+		flags.PrintDefaults(fs)
+		Expect(buf.String()).To(ContainSubstring("-server.port"))
+		Expect(buf.String()).NotTo(ContainSubstring("http2-workers"))
+
+		buf.Reset()
+		fs.Usage()
+		Expect(buf.String()).To(HavePrefix("Usage of test:\n"))
+		Expect(buf.String()).To(ContainSubstring("-server.port"))
+		Expect(buf.String()).NotTo(ContainSubstring("http2-workers"))
+	})
+
+	It("prints flags in the order of their fields", func() {
+		var buf bytes.Buffer
+		fs.SetOutput(&buf)
+		flags.PrintDefaults(fs)
+		Expect(buf.String()).To(MatchRegexp(`(?s)-server\.listen-address.*-server\.port.*-server\.timeout.*-database\.url.*-debug`))
+
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Bool("verbose", false, "verbose output")
+		Expect(flags.RegisterFor(fs, &conf, logger, flags.WithFieldOrder(loader.AlphabeticalOrder))).To(Succeed())
+		buf.Reset()
+		fs.SetOutput(&buf)
+		flags.PrintDefaults(fs)
+		Expect(buf.String()).To(MatchRegexp(`(?s)-verbose.*-database\.password.*-database\.url.*-debug.*-server\.allowed-origins.*-server\.timeout`))
+	})
+
+	It("writes parsed flags back into the config", func() {
+		Expect(fs.Parse([]string{
+			"-server.port=9090",
+			"-server.timeout", "5s",
+			"-server.allowed-origins", "a.example.com,b.example.com",
+			"-database.password", "hunter2",
+			"-debug",
+		})).To(Succeed())
+		Expect(conf).To(Equal(testdata.Config{
+			Server: testdata.ServerConfig{
+				ListenAddress:  "localhost",
+				Port:           9090,
+				Timeout:        5 * time.Second,
+				AllowedOrigins: []string{"a.example.com", "b.example.com"},
+			},
+			Database: &testdata.DatabaseConfig{Password: "hunter2"},
+			Debug:    true,
+		}))
+	})
+
+	It("does not show sensitive defaults", func() {
+		conf.Database = &testdata.DatabaseConfig{Password: "hunter2"}
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		Expect(flags.RegisterFor(fs, &conf, logger)).To(Succeed())
+		Expect(fs.Lookup("database.password").DefValue).To(BeEmpty())
+	})
+
+	It("reports invalid values", func() {
+		Expect(fs.Parse([]string{"-server.port=http"})).NotTo(Succeed())
+	})
+
+	It("rejects non-pointer configs", func() {
+		Expect(flags.RegisterFor(flag.NewFlagSet("test", flag.ContinueOnError), conf, logger)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,22 @@
+package flags_test
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/testutils"
+)
+
+var logger logr.Logger
+
+func TestFlags(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Flags Suite")
+}
+
+var _ = BeforeEach(func() {
+	logger = &testutils.GinkgoLogger{Writer: GinkgoWriter}
+})
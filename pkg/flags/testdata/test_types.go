@@ -0,0 +1,37 @@
+package testdata
+
+import "time"
+
+// Config is an application config.
+type Config struct {
+	// Server configures the server.
+	Server ServerConfig `json:"server"`
+	// Database configures the database.
+	Database *DatabaseConfig `json:"database,omitempty"`
+	// Debug enables debug logging.
+	Debug bool `json:"debug,omitempty"`
+}
+
+// ServerConfig configures the server.
+type ServerConfig struct {
+	// ListenAddress is the address to listen on.
+	ListenAddress string `json:"listenAddress"`
+	// Port is the port to listen on. It is ignored if [ServerConfig.ListenAddress] includes a
+	// port.
+	Port int `json:"port"`
+	// Timeout is the request timeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// AllowedOrigins are the allowed CORS origins.
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// HTTP2Workers is the number of HTTP/2 workers, which is not documented.
+	HTTP2Workers int `json:"http2Workers,omitempty" prettyconf:"hidden"`
+}
+
+// DatabaseConfig configures the database.
+type DatabaseConfig struct {
+	// URL is the database URL.
+	URL string `json:"url"`
+	// Password is the database
+	// password.
+	Password string `json:"password" prettyconf:"secret"`
+}
@@ -0,0 +1,285 @@
+// Package layered loads configs from layers of defaults, config files, environment variables and
+// command-line flags, recording where the value of every key was loaded from.
+package layered
+
+import (
+	"encoding/json"
+	"flag"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jimmidyson/prettyconf/pkg/env"
+	"github.com/jimmidyson/prettyconf/pkg/flags"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// Option configures the layers that are loaded.
+type Option func(*layers)
+
+type layers struct {
+	files     []string
+	envPrefix *string
+	lookup    env.Lookup
+	flagSet   *flag.FlagSet
+	args      []string
+}
+
+// WithFile adds a YAML or JSON config file layer. Files are loaded in the order they are added,
+// with later files overriding earlier ones: mappings are merged and lists are replaced.
+func WithFile(path string) Option {
+	return func(l *layers) {
+		l.files = append(l.files, path)
+	}
+}
+
+// WithEnv adds an environment variable layer, with variables named with prefix as described by
+// env.Vars.
+func WithEnv(prefix string) Option {
+	return func(l *layers) {
+		l.envPrefix = &prefix
+	}
+}
+
+// WithLookup sets the function used to look up environment variables. The default is
+// os.LookupEnv.
+func WithLookup(lookup env.Lookup) Option {
+	return func(l *layers) {
+		l.lookup = lookup
+	}
+}
+
+// WithFlags adds a command-line flag layer. Flags for the config fields are registered on fs as by
+// flags.Register, after the file and environment layers are applied so that flag defaults show the
+// values they override, and then fs is parsed with args. Other flags can be defined on fs before
+// loading.
+func WithFlags(fs *flag.FlagSet, args []string) Option {
+	return func(l *layers) {
+		l.flagSet = fs
+		l.args = args
+	}
+}
+
+// Load loads the type of the config that conf points to and applies the layers onto it. See
+// LoadType.
+func Load(conf interface{}, logger logr.Logger, opts ...Option) (Origins, error) {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return nil, err
+	}
+	return LoadType(conf, rootType, packages, opts...)
+}
+
+// LoadType applies the layers onto the config that conf points to, using the loaded rootType and
+// packages. The value of conf when it is passed in is the default layer. Layers are applied in
+// order of precedence: config files, then environment variables, then flags. The returned origins
+// record the layer that set each value; values that were not set by any layer have the Default
+// origin.
+func LoadType(conf interface{}, rootType loader.Type, packages []loader.Package, opts ...Option) (Origins, error) {
+	if v := reflect.ValueOf(conf); v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, errors.Errorf("conf must be a non-nil pointer, got %T", conf)
+	}
+	l := &layers{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	origins := Origins{}
+	for _, file := range l.files {
+		if err := applyFile(conf, rootType, packages, file, origins); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.envPrefix != nil {
+		if err := applyEnv(conf, env.Vars(rootType, packages, *l.envPrefix), l.lookup, origins); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.flagSet != nil {
+		if err := applyFlags(conf, rootType, packages, l.flagSet, l.args, origins); err != nil {
+			return nil, err
+		}
+	}
+	return origins, nil
+}
+
+func applyFile(conf interface{}, rootType loader.Type, packages []loader.Package, file string, origins Origins) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read config file %s", file)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return errors.Wrapf(err, "failed to parse config file %s", file)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+
+	// Decoding through JSON applies the json struct tags of the config and merges onto its
+	// current values.
+	var values interface{}
+	if err := document.Content[0].Decode(&values); err != nil {
+		return errors.Wrapf(err, "failed to parse config file %s", file)
+	}
+	data, err = json.Marshal(values)
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert config file %s to JSON", file)
+	}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return errors.Wrapf(err, "failed to decode config file %s", file)
+	}
+
+	recorder := &fileOrigins{file: file, packages: packages, origins: origins}
+	if document.Content[0].Kind == yaml.MappingNode {
+		recorder.recordMapping(document.Content[0], rootType, "")
+	} else {
+		recorder.record(document.Content[0], nil, "")
+	}
+	return nil
+}
+
+// fileOrigins records the origins of the values set by a config file.
+type fileOrigins struct {
+	file     string
+	packages []loader.Package
+	origins  Origins
+}
+
+// recordMapping records the origins of the values of the fields of pkgType set in the mapping node
+// at path. Keys are matched to fields as encoding/json does, preferring an exact match of the JSON
+// property and otherwise ignoring case, and the origins are recorded under the JSON properties of
+// the fields. Keys that match no field are not decoded, so they have no origin.
+func (f *fileOrigins) recordMapping(node *yaml.Node, pkgType loader.Type, path string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		field, found := jsonField(pkgType, node.Content[i].Value)
+		if !found {
+			continue
+		}
+		f.record(node.Content[i+1], field.Type, loader.JoinKeyPath(path, field.JSONProperty))
+	}
+}
+
+// record records the origins of the leaf values under node, which is a value of valueType at the
+// key path path. Mappings are merged, so an empty mapping keeps the origins under it. Lists replace
+// earlier values, so the origins of any values under them are cleared. A null is recorded as a
+// value set by the file, as it clears pointers, maps and lists. valueType is nil for values that
+// are not of a known type, whose keys are recorded as they are.
+func (f *fileOrigins) record(node *yaml.Node, valueType types.Type, path string) {
+	switch {
+	case node.Kind == yaml.AliasNode:
+		f.record(node.Alias, valueType, path)
+	case node.Kind == yaml.MappingNode:
+		if pkgType, found := f.structType(valueType); found {
+			f.recordMapping(node, pkgType, path)
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			f.record(node.Content[i+1], elemType(valueType), loader.JoinKeyPath(path, node.Content[i].Value))
+		}
+	case node.Kind == yaml.SequenceNode && len(node.Content) > 0:
+		f.origins.clear(path)
+		for i, item := range node.Content {
+			f.record(item, elemType(valueType), loader.IndexKeyPath(path, i))
+		}
+	default:
+		f.origins.set(path, Origin{Kind: File, Name: f.file, Line: node.Line})
+	}
+}
+
+// structType returns the loaded struct type of values of valueType, through pointers.
+func (f *fileOrigins) structType(valueType types.Type) (loader.Type, bool) {
+	for {
+		pointer, ok := valueType.(*types.Pointer)
+		if !ok {
+			break
+		}
+		valueType = pointer.Elem()
+	}
+	if valueType == nil {
+		return loader.Type{}, false
+	}
+	return loader.FindNamedType(f.packages, valueType)
+}
+
+// elemType returns the type of the items of lists and the values of maps of valueType, or nil if
+// valueType is neither.
+func elemType(valueType types.Type) types.Type {
+	if valueType == nil {
+		return nil
+	}
+	switch t := valueType.Underlying().(type) {
+	case *types.Pointer:
+		return elemType(t.Elem())
+	case *types.Slice:
+		return t.Elem()
+	case *types.Array:
+		return t.Elem()
+	case *types.Map:
+		return t.Elem()
+	default:
+		return nil
+	}
+}
+
+// jsonField returns the field of pkgType that encoding/json decodes key into.
+func jsonField(pkgType loader.Type, key string) (loader.Field, bool) {
+	for _, field := range pkgType.Fields {
+		if field.JSONProperty != "" && field.JSONProperty == key {
+			return field, true
+		}
+	}
+	for _, field := range pkgType.Fields {
+		if field.JSONProperty != "" && strings.EqualFold(field.JSONProperty, key) {
+			return field, true
+		}
+	}
+	return loader.Field{}, false
+}
+
+func applyEnv(conf interface{}, vars []env.Var, lookup env.Lookup, origins Origins) error {
+	var errs env.Errors
+	for _, v := range vars {
+		value, ok := lookup(v.Name)
+		if !ok {
+			continue
+		}
+		if err := v.Set(conf, value); err != nil {
+			errs = append(errs, &env.VarError{Var: v, Value: value, Err: err})
+			continue
+		}
+		origins.set(v.Path, Origin{Kind: Env, Name: v.Name})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func applyFlags(conf interface{}, rootType loader.Type, packages []loader.Package, fs *flag.FlagSet, args []string, origins Origins) error {
+	if err := flags.Register(fs, conf, rootType, packages); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	paths := map[string]string{}
+	for _, v := range env.Vars(rootType, packages, "") {
+		paths[flags.Name(v.Path)] = v.Path
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if path, ok := paths[f.Name]; ok {
+			origins.set(path, Origin{Kind: Flag, Name: f.Name})
+		}
+	})
+	return nil
+}
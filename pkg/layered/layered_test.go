@@ -0,0 +1,123 @@
+package layered_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/layered"
+	"github.com/jimmidyson/prettyconf/pkg/layered/testdata"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+var _ = Describe("Layered", func() {
+	var (
+		conf        testdata.Config
+		environment map[string]string
+		fs          *flag.FlagSet
+		appYAML     = filepath.Join("testdata", "app.yaml")
+	)
+
+	lookup := func(name string) (string, bool) {
+		value, ok := environment[name]
+		return value, ok
+	}
+
+	BeforeEach(func() {
+		conf = testdata.Config{
+			Server:  testdata.ServerConfig{Host: "localhost", Port: 80},
+			Timeout: time.Second,
+		}
+		environment = map[string]string{"APP_SERVER_PORT": "9090"}
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(GinkgoWriter)
+	})
+
+	It("applies layers in order of precedence", func() {
+		origins, err := layered.Load(&conf, logger,
+			layered.WithFile(appYAML),
+			layered.WithEnv("APP"), layered.WithLookup(lookup),
+			layered.WithFlags(fs, []string{"-debug", "-tags=c"}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf).To(Equal(testdata.Config{
+			Server:  testdata.ServerConfig{Host: "example.com", Port: 9090, Labels: map[string]string{"zone": "a"}},
+			Tags:    []string{"c"},
+			Timeout: time.Second,
+			Debug:   true,
+		}))
+		Expect(origins).To(Equal(layered.Origins{
+			"server.host":        {Kind: layered.File, Name: appYAML, Line: 2},
+			"server.port":        {Kind: layered.Env, Name: "APP_SERVER_PORT"},
+			"server.labels.zone": {Kind: layered.File, Name: appYAML, Line: 5},
+			"tags":               {Kind: layered.Flag, Name: "tags"},
+			"debug":              {Kind: layered.Flag, Name: "debug"},
+		}))
+	})
+
+	It("finds the origin of every value", func() {
+		origins, err := layered.Load(&conf, logger, layered.WithFile(appYAML), layered.WithEnv("APP"),
+			layered.WithLookup(lookup))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(origins.Of("tags[1]")).To(Equal(layered.Origin{Kind: layered.File, Name: appYAML, Line: 8}))
+		Expect(origins.Of("timeout")).To(Equal(layered.Origin{Kind: layered.Default}))
+		Expect(origins.Comment("server.port")).To(Equal("from: env APP_SERVER_PORT"))
+		Expect(origins.Comment("server.host")).To(Equal("from: testdata/app.yaml:2"))
+		Expect(origins.Comment("timeout")).To(Equal("from: default"))
+	})
+
+	It("keeps origins under empty mappings and records nulls", func() {
+		overrideYAML := filepath.Join("testdata", "override.yaml")
+		origins, err := layered.Load(&conf, logger, layered.WithFile(appYAML), layered.WithFile(overrideYAML))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf.Server).To(Equal(testdata.ServerConfig{Host: "example.com", Port: 8080, Labels: map[string]string{"zone": "a"}}))
+		Expect(conf.Tags).To(BeNil())
+		Expect(origins).To(Equal(layered.Origins{
+			"server.host":        {Kind: layered.File, Name: appYAML, Line: 2},
+			"server.port":        {Kind: layered.File, Name: appYAML, Line: 3},
+			"server.labels.zone": {Kind: layered.File, Name: appYAML, Line: 5},
+			"tags":               {Kind: layered.File, Name: overrideYAML, Line: 2},
+		}))
+	})
+
+	It("records origins of keys that only match fields when ignoring case under their JSON names", func() {
+		caseYAML := filepath.Join("testdata", "case.yaml")
+		origins, err := layered.Load(&conf, logger, layered.WithFile(appYAML), layered.WithFile(caseYAML))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf.Server.Port).To(Equal(9090))
+		Expect(conf.Server.Labels).To(Equal(map[string]string{"zone": "a", "Zone": "b"}))
+		Expect(origins.Of("server.port")).To(Equal(layered.Origin{Kind: layered.File, Name: caseYAML, Line: 2}))
+		Expect(origins.Of("server.labels.Zone")).To(Equal(layered.Origin{Kind: layered.File, Name: caseYAML, Line: 4}))
+		Expect(origins).NotTo(HaveKey("Server.Port"))
+		Expect(origins).NotTo(HaveKey("unknown"))
+	})
+
+	It("returns invalid environment variables", func() {
+		environment["APP_SERVER_PORT"] = "http"
+		_, err := layered.Load(&conf, logger, layered.WithEnv("APP"), layered.WithLookup(lookup))
+		Expect(err).To(MatchError(ContainSubstring(`invalid value "http" for APP_SERVER_PORT (server.port)`)))
+	})
+
+	It("annotates printed configs with origins", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		origins, err := layered.Load(&conf, logger,
+			layered.WithFile(appYAML),
+			layered.WithEnv("APP"), layered.WithLookup(lookup),
+			layered.WithFlags(fs, []string{"-debug"}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(conf, w, logger, printer.WithKeyComments(origins.Comment))).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+})
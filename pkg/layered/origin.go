@@ -0,0 +1,84 @@
+package layered
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// SourceKind is the kind of layer a value was loaded from.
+type SourceKind string
+
+const (
+	// Default is used for values that were not set by any layer and keep the value they had when
+	// the config was loaded.
+	Default SourceKind = "default"
+	// File is used for values set by a config file.
+	File SourceKind = "file"
+	// Env is used for values set by an environment variable.
+	Env SourceKind = "env"
+	// Flag is used for values set by a command-line flag.
+	Flag SourceKind = "flag"
+)
+
+// Origin is where a config value was loaded from.
+type Origin struct {
+	Kind SourceKind
+	// Name is the path of the config file, the name of the environment variable or the name of
+	// the flag.
+	Name string
+	// Line is the line of the value in the config file.
+	Line int
+}
+
+// String returns the origin as `default`, `/etc/app.yaml:12`, `env APP_SERVER_PORT` or
+// `flag --server.port`.
+func (o Origin) String() string {
+	switch o.Kind {
+	case File:
+		return fmt.Sprintf("%s:%d", o.Name, o.Line)
+	case Env:
+		return "env " + o.Name
+	case Flag:
+		return "flag --" + o.Name
+	default:
+		return string(Default)
+	}
+}
+
+// Origins maps the key paths of the values set by layers, e.g. `server.port` or `servers[0].url`,
+// to their origins.
+type Origins map[string]Origin
+
+// Of returns the origin of the value at path: the origin of path or of its closest parent that
+// was set as a whole, such as a list set from an environment variable, or Default.
+func (o Origins) Of(path string) Origin {
+	for ; path != ""; path = loader.ParentKeyPath(path) {
+		if origin, ok := o[path]; ok {
+			return origin
+		}
+	}
+	return Origin{Kind: Default}
+}
+
+// Comment returns the origin of the value at path as a comment, e.g. `from: env APP_SERVER_PORT`,
+// for use with printer.WithKeyComments.
+func (o Origins) Comment(path string) string {
+	return "from: " + o.Of(path).String()
+}
+
+// set records origin for the value at path, replacing the origins of any values under it.
+func (o Origins) set(path string, origin Origin) {
+	o.clear(path)
+	o[path] = origin
+}
+
+// clear removes the origins of path and any values under it.
+func (o Origins) clear(path string) {
+	for p := range o {
+		if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			delete(o, p)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package layered_test
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/testutils"
+)
+
+var logger logr.Logger
+
+func TestLayered(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Layered Suite")
+}
+
+var _ = BeforeEach(func() {
+	logger = &testutils.GinkgoLogger{Writer: GinkgoWriter}
+})
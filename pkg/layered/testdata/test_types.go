@@ -0,0 +1,25 @@
+package testdata
+
+import "time"
+
+// Config is the config of an app.
+type Config struct {
+	// Server configures the server.
+	Server ServerConfig `json:"server"`
+	// Tags are added to every request.
+	Tags []string `json:"tags,omitempty"`
+	// Timeout is how long to wait for requests.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Debug enables debug logging.
+	Debug bool `json:"debug,omitempty"`
+}
+
+// ServerConfig configures the server.
+type ServerConfig struct {
+	// Host is the host to listen on.
+	Host string `json:"host"`
+	// Port is the port to listen on.
+	Port int `json:"port"`
+	// Labels are attached to the server.
+	Labels map[string]string `json:"labels,omitempty"`
+}
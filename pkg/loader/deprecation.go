@@ -0,0 +1,37 @@
+package loader
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Deprecation describes a deprecated type or field, from the "Deprecated:" paragraph of its doc
+// comment.
+type Deprecation struct {
+	// Message is the text of the paragraph following "Deprecated:".
+	Message string
+	// Replacement is the name of the replacement if the message names one, e.g. "Use NewField
+	// instead."
+	Replacement string
+}
+
+var replacementRegexp = regexp.MustCompile(`(?i)\buse\s+(\S+)\s+instead\b`)
+
+// extractDeprecation removes the "Deprecated:" paragraph from doc and returns it as a Deprecation.
+// If there is no such paragraph the returned Deprecation is nil.
+func extractDeprecation(doc string) (string, *Deprecation) {
+	paragraphs := strings.Split(doc, "\n\n")
+	for i, paragraph := range paragraphs {
+		if !strings.HasPrefix(paragraph, "Deprecated:") {
+			continue
+		}
+		message := strings.Join(strings.Fields(strings.TrimPrefix(paragraph, "Deprecated:")), " ")
+		deprecation := &Deprecation{Message: message}
+		if match := replacementRegexp.FindStringSubmatch(message); match != nil {
+			deprecation.Replacement = strings.Trim(match[1], "`[]\"'.,;:")
+		}
+		remaining := append(paragraphs[:i:i], paragraphs[i+1:]...)
+		return strings.TrimSpace(strings.Join(remaining, "\n\n")), deprecation
+	}
+	return doc, nil
+}
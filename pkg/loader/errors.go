@@ -0,0 +1,20 @@
+package loader
+
+import "github.com/pkg/errors"
+
+// SourceError is returned by Load when the source of the requested packages cannot be found or
+// type checked. Errors in loaded source, such as invalid doc comment markers or struct tags, are
+// not SourceErrors.
+type SourceError struct {
+	Err error
+}
+
+func (e *SourceError) Error() string {
+	return e.Err.Error()
+}
+
+// IsSourceError returns true if the cause of err is a SourceError.
+func IsSourceError(err error) bool {
+	_, ok := errors.Cause(err).(*SourceError)
+	return ok
+}
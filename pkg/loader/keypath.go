@@ -0,0 +1,51 @@
+package loader
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Key paths address values in config documents. Keys are separated by dots and list items are
+// addressed by their index in brackets, e.g. `servers[0].url`. Paths that address every item of a
+// list or every value of a map use `[*]` and `*`, e.g. `servers[*].url` and `labels.*`.
+
+// JoinKeyPath returns the key path of the value under key in the mapping at path.
+func JoinKeyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// IndexKeyPath returns the key path of the item at index in the list at path.
+func IndexKeyPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+// AllItemsKeyPath returns the key path that addresses every item of the list at path.
+func AllItemsKeyPath(path string) string {
+	return path + "[*]"
+}
+
+// AllValuesKeyPath returns the key path that addresses every value of the map at path.
+func AllValuesKeyPath(path string) string {
+	return JoinKeyPath(path, "*")
+}
+
+// ParentKeyPath returns the key path of the mapping or list holding the value at path, or an empty
+// string for top-level keys.
+func ParentKeyPath(path string) string {
+	if idx := strings.LastIndexAny(path, ".["); idx > -1 {
+		return path[:idx]
+	}
+	return ""
+}
+
+// SplitKeyPath returns the keys and list indexes of path, e.g. `servers`, `0` and `url` for
+// `servers[0].url`.
+func SplitKeyPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(strings.NewReplacer("[", ".", "]", "").Replace(path), ".")
+}
@@ -0,0 +1,30 @@
+package loader_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+var _ = Describe("Key paths", func() {
+	It("formats keys, list indexes and wildcards", func() {
+		Expect(JoinKeyPath("", "servers")).To(Equal("servers"))
+		Expect(JoinKeyPath(IndexKeyPath("servers", 0), "url")).To(Equal("servers[0].url"))
+		Expect(JoinKeyPath(AllItemsKeyPath("servers"), "url")).To(Equal("servers[*].url"))
+		Expect(AllValuesKeyPath("labels")).To(Equal("labels.*"))
+	})
+
+	It("finds parents", func() {
+		Expect(ParentKeyPath("servers[0].url")).To(Equal("servers[0]"))
+		Expect(ParentKeyPath("servers[0]")).To(Equal("servers"))
+		Expect(ParentKeyPath("labels.*")).To(Equal("labels"))
+		Expect(ParentKeyPath("servers")).To(BeEmpty())
+	})
+
+	It("splits key paths into keys and indexes", func() {
+		Expect(SplitKeyPath("servers[0].url")).To(Equal([]string{"servers", "0", "url"}))
+		Expect(SplitKeyPath("matrix[1][2]")).To(Equal([]string{"matrix", "1", "2"}))
+		Expect(SplitKeyPath("")).To(BeEmpty())
+	})
+})
@@ -3,8 +3,10 @@ package loader
 import (
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/types"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -19,10 +21,21 @@ type ASTLoader struct {
 	requestedPackages []string
 	logger            logr.Logger
 	prog              *loader.Program
+	includeTypes      []string
+	excludeTypes      []string
+	rootMarker        string
+	dir               string
+	packages          []Package
 }
 
-func New(packages []string, logger logr.Logger) *ASTLoader {
-	return &ASTLoader{requestedPackages: packages, logger: logger}
+// New returns a loader for the requested packages. Packages can be import paths or patterns
+// understood by the go command, such as `./...`.
+func New(packages []string, logger logr.Logger, opts ...Option) *ASTLoader {
+	l := &ASTLoader{requestedPackages: packages, logger: logger}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 type Package struct {
@@ -32,10 +45,14 @@ type Package struct {
 }
 
 type Type struct {
-	Name           string
-	Package        string
-	Fields         []Field
-	Doc            string
+	Name       string
+	Package    string
+	Fields     []Field
+	Doc        string
+	Markers    Markers
+	Union      *Union
+	Deprecated *Deprecation
+	Position   Position
 }
 
 type Field struct {
@@ -46,23 +63,48 @@ type Field struct {
 	JSONProperty string
 	Type         types.Type
 	TypeName     string
+	Sensitive    bool
+	Hidden       bool
+	Order        int
+	Section      string
+	Example      string
+	Summary      string
+	Env          string
+	Enum         []string
+	Deprecated   *Deprecation
+	Markers      Markers
+	Position     Position
 }
 
 func (l *ASTLoader) Load() ([]Package, error) {
 	var conf loader.Config
 	conf.ParserMode = parser.ParseComments
+	if l.dir != "" {
+		dir, err := filepath.Abs(l.dir)
+		if err != nil {
+			return nil, &SourceError{Err: errors.Wrapf(err, "invalid directory %s", l.dir)}
+		}
+		buildContext := build.Default
+		buildContext.Dir = dir
+		conf.Build = &buildContext
+		conf.Cwd = dir
+	}
 
-	for _, pkg := range l.requestedPackages {
+	requestedPackages, err := expandPatterns(l.requestedPackages, l.dir)
+	if err != nil {
+		return nil, &SourceError{Err: err}
+	}
+	for _, pkg := range requestedPackages {
 		conf.Import(pkg)
 	}
 
 	prog, err := conf.Load()
 	if err != nil {
-		return nil, errors.Wrap(err, "cannot load requested packages")
+		return nil, &SourceError{Err: errors.Wrap(err, "cannot load requested packages")}
 	}
 	l.prog = prog
 
-	loadedPackages := make([]Package, 0, len(l.requestedPackages))
+	loadedPackages := make([]Package, 0, len(requestedPackages))
 	for _, pkg := range prog.InitialPackages() {
 		pkgPath := pkg.Pkg.Path()
 
@@ -107,27 +149,13 @@ func (l *ASTLoader) Load() ([]Package, error) {
 						continue
 					}
 
-					jsonProperty := fld.Name()
-					required := true
 					fldTag := structType.Tag(j)
-					tags, err := ParseStructTags(fldTag)
+					tags, err := ParseFieldTags(fld.Name(), fldTag)
 					if err != nil {
-						return nil, errors.Wrapf(err, "failed to parse struct tag `%s`", fldTag)
-					}
-
-					for _, t := range tags {
-						if t.Name == "json" {
-							split := strings.Split(t.Value, ",")
-							jsonProperty = split[0]
-							for _, tagValue := range split[1:] {
-								if tagValue == "omitempty" {
-									required = false
-									break
-								}
-							}
-							break
-						}
+						return nil, err
 					}
+					jsonProperty, required, prettyconfTag := tags.JSONProperty, tags.Required, tags.Prettyconf
+					sensitive := prettyconfTag.Secret || isSensitiveType(fld.Type())
 
 					if jsonProperty == "-" {
 						l.logger.V(5).Info("ignoring struct field as not serialized", "struct", t.Name.Name, "field", fld.Name())
@@ -135,29 +163,48 @@ func (l *ASTLoader) Load() ([]Package, error) {
 					}
 
 					l.logger.V(5).Info("adding struct field", "struct", t.Name.Name, "field", fld.Name(), "type", fld.Type().String())
-					fldDoc := ""
+					var (
+						fldDoc     string
+						fldMarkers Markers
+					)
 					if j < astStructType.Fields.NumFields() {
-						fldDoc = strings.TrimSpace(astStructType.Fields.List[j].Doc.Text())
-						docLines := strings.Split(fldDoc, "\n")
-						for i := len(docLines) - 1; i >= 0; i-- {
-							if strings.HasPrefix(strings.TrimSpace(docLines[i]), "+optional") {
-								required = false
-							}
+						fldDoc, fldMarkers = ExtractMarkers(astStructType.Fields.List[j].Doc.Text())
+						if fldMarkers.Has("optional") {
+							required = false
+						}
+						if fldMarkers.Has(SensitiveMarker) {
+							sensitive = true
 						}
 					}
-
-					typeName := fld.Type().String()
-					if idx := strings.Index(typeName, "vendor/"); idx > -1 {
-						typeName = typeName[idx+len("vendor/"):]
+					fldDoc, fldDeprecation := extractDeprecation(fldDoc)
+					fldDoc = overrideSummary(fldDoc, prettyconfTag.Summary)
+					order := prettyconfTag.Order
+					if orderMarker, ok := fldMarkers.Get(OrderMarker); ok && order == 0 {
+						order, err = strconv.Atoi(orderMarker)
+						if err != nil {
+							return nil, errors.Wrapf(err, "invalid +%s marker on field %s.%s", OrderMarker, t.Name.Name, fld.Name())
+						}
 					}
+
 					f := Field{
 						Name:         fld.Name(),
 						Doc:          fldDoc,
 						Type:         fld.Type(),
-						TypeName:     typeName,
+						TypeName:     typeName(fld.Type()),
 						Anonymous:    fld.Anonymous(),
 						JSONProperty: jsonProperty,
 						JSONRequired: required,
+						Sensitive:    sensitive,
+						Hidden:       prettyconfTag.Hidden,
+						Order:        order,
+						Enum:         enumValues(fldMarkers),
+						Deprecated:   fldDeprecation,
+						Section:      prettyconfTag.Section,
+						Example:      prettyconfTag.Example,
+						Summary:      prettyconfTag.Summary,
+						Env:          prettyconfTag.Env,
+						Markers:      fldMarkers,
+						Position:     newPosition(prog.Fset, fld.Pos()),
 					}
 					structFields = append(structFields, f)
 					l.logger.V(5).Info("added struct field definition", "struct", t.Name.Name, "field", f)
@@ -167,11 +214,21 @@ func (l *ASTLoader) Load() ([]Package, error) {
 					continue
 				}
 
+				typeDoc, typeMarkers := ExtractMarkers(astutils.TypeDoc(pkgDoc, currentObj.Name))
+				typeDoc, typeDeprecation := extractDeprecation(typeDoc)
+				union, err := unionFromMarkers(currentObj.Name, typeMarkers, structFields)
+				if err != nil {
+					return nil, err
+				}
 				apiType := Type{
-					Name:           currentObj.Name,
-					Package:        pkgPath,
-					Doc:            strings.TrimSpace(astutils.TypeDoc(pkgDoc, currentObj.Name)),
-					Fields:         structFields,
+					Name:       currentObj.Name,
+					Package:    pkgPath,
+					Doc:        typeDoc,
+					Fields:     structFields,
+					Markers:    typeMarkers,
+					Union:      union,
+					Deprecated: typeDeprecation,
+					Position:   newPosition(prog.Fset, t.Name.Pos()),
 				}
 				exportedTypes = append(exportedTypes, apiType)
 			}
@@ -189,10 +246,63 @@ func (l *ASTLoader) Load() ([]Package, error) {
 		}
 		loadedPackages = append(loadedPackages, loadedPackage)
 	}
+	loadedPackages, err = l.filterTypes(loadedPackages)
+	if err != nil {
+		return nil, err
+	}
+	l.packages = loadedPackages
 
 	return loadedPackages, nil
 }
 
+// FieldTags holds the parts of a struct field's tags that the loader uses.
+type FieldTags struct {
+	// JSONProperty is the name in the `json` tag, or the field name if the field has no `json`
+	// tag.
+	JSONProperty string
+	// Required is false if the `json` tag has the omitempty option.
+	Required   bool
+	Prettyconf PrettyconfTag
+}
+
+// ParseFieldTags parses the `json` and `prettyconf` tags of the field fieldName.
+func ParseFieldTags(fieldName, fieldTag string) (FieldTags, error) {
+	parsed := FieldTags{JSONProperty: fieldName, Required: true}
+	tags, err := ParseStructTags(fieldTag)
+	if err != nil {
+		return FieldTags{}, errors.Wrapf(err, "failed to parse struct tag `%s`", fieldTag)
+	}
+
+	for _, t := range tags {
+		switch t.Name {
+		case "json":
+			split := strings.Split(t.Value, ",")
+			parsed.JSONProperty = split[0]
+			for _, tagValue := range split[1:] {
+				if tagValue == "omitempty" {
+					parsed.Required = false
+					break
+				}
+			}
+		case PrettyconfTagName:
+			parsed.Prettyconf, err = ParsePrettyconfTag(t.Value)
+			if err != nil {
+				return FieldTags{}, errors.Wrapf(err, "failed to parse struct tag `%s`", fieldTag)
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// typeName returns the string form of t with any vendor directory prefix removed.
+func typeName(t types.Type) string {
+	name := t.String()
+	if idx := strings.Index(name, "vendor/"); idx > -1 {
+		name = name[idx+len("vendor/"):]
+	}
+	return name
+}
+
 type StructTag struct {
 	Name  string
 	Value string
@@ -222,8 +332,29 @@ func (tags StructTags) Has(name string) bool {
 }
 
 // ParseStructTags returns the full set of fields in a struct tag in the order they appear in
-// the struct tag.
+// the struct tag. Parsing stops at the first malformed field, as it does for reflect.StructTag;
+// use ValidateStructTag to detect those.
 func ParseStructTags(tag string) (StructTags, error) {
+	tags, _, err := parseStructTags(tag)
+	return tags, err
+}
+
+// ValidateStructTag returns an error if the struct tag has a malformed field that ParseStructTags
+// would stop at.
+func ValidateStructTag(tag string) error {
+	_, rest, err := parseStructTags(tag)
+	if err != nil {
+		return err
+	}
+	if rest = strings.TrimLeft(rest, " "); rest != "" {
+		return errors.Errorf("malformed struct tag at `%s`", rest)
+	}
+	return nil
+}
+
+// parseStructTags parses tag, returning the parsed fields and the remainder of the tag from the
+// first malformed field.
+func parseStructTags(tag string) (StructTags, string, error) {
 	tags := StructTags{}
 	for tag != "" {
 		// Skip leading space.
@@ -235,6 +366,7 @@ func ParseStructTags(tag string) (StructTags, error) {
 		if tag == "" {
 			break
 		}
+		field := tag
 
 		// Scan to colon. A space, a quote or a control character is a syntax error.
 		// Strictly speaking, control chars include the range [0x7f, 0x9f], not just
@@ -245,7 +377,7 @@ func ParseStructTags(tag string) (StructTags, error) {
 			i++
 		}
 		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
-			break
+			return tags, field, nil
 		}
 		name := string(tag[:i])
 		tag = tag[i+1:]
@@ -259,16 +391,16 @@ func ParseStructTags(tag string) (StructTags, error) {
 			i++
 		}
 		if i >= len(tag) {
-			break
+			return tags, field, nil
 		}
 		qvalue := string(tag[:i+1])
 		tag = tag[i+1:]
 
 		value, err := strconv.Unquote(qvalue)
 		if err != nil {
-			return nil, err
+			return nil, field, err
 		}
 		tags = append(tags, StructTag{Name: name, Value: value})
 	}
-	return tags, nil
+	return tags, "", nil
 }
@@ -2,6 +2,7 @@ package loader_test
 
 import (
 	"go/types"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -22,11 +23,25 @@ func typeFromPackage(pkg Package, typeName, fieldName string) types.Type {
 	return nil
 }
 
+func position(filename string, line, column int) Position {
+	filename, err := filepath.Abs(filename)
+	Expect(err).NotTo(HaveOccurred())
+	return Position{Filename: filename, Line: line, Column: column}
+}
+
 var _ = Describe("Loader", func() {
 	It("errors for unknown packages", func() {
 		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/unknown"}, logger)
 		_, err := loader.Load()
 		Expect(err).To(HaveOccurred())
+		Expect(IsSourceError(err)).To(BeTrue())
+	})
+
+	It("errors for invalid markers", func() {
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/invalid"}, logger)
+		_, err := loader.Load()
+		Expect(err).To(MatchError(`invalid +order marker on field Config.Name: strconv.Atoi: parsing "first": invalid syntax`))
+		Expect(IsSourceError(err)).To(BeFalse())
 	})
 
 	It("parses single packages", func() {
@@ -34,6 +49,7 @@ var _ = Describe("Loader", func() {
 		pkgs, err := loader.Load()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(pkgs).To(HaveLen(1))
+		file1 := "testdata/pkg1/file1.go"
 		Expect(pkgs).To(Equal([]Package{
 			{
 				Path: "github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1",
@@ -42,26 +58,249 @@ var _ = Describe("Loader", func() {
 						Name:    "Type1",
 						Package: "github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1",
 						Fields: []Field{
-							{Name: "Field1", Doc: "Some doc.", Anonymous: false, JSONRequired: true, JSONProperty: "Field1", Type: types.Typ[types.Int], TypeName: "int"},
-							{Name: "Field2", Doc: "", Anonymous: false, JSONRequired: true, JSONProperty: "f2", Type: types.Typ[types.String], TypeName: "string"},
-							{Name: "Field4", Doc: "Even more doc.", Anonymous: false, JSONRequired: false, JSONProperty: "", Type: types.NewSlice(types.Typ[types.String]), TypeName: "[]string"},
-							{Name: "Field5", Doc: "And some\nmore doc.", Anonymous: false, JSONRequired: false, JSONProperty: "f5", Type: types.NewMap(types.Typ[types.String], types.Typ[types.Bool]), TypeName: "map[string]bool"},
-							{Name: "Type5", Doc: "", Anonymous: true, JSONRequired: false, JSONProperty: "", Type: typeFromPackage(pkgs[0], "Type1", "Type5"), TypeName: "github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1.Type5"},
-							{Name: "Type5s", Doc: "", JSONRequired: false, JSONProperty: "t5s", Type: typeFromPackage(pkgs[0], "Type1", "Type5s"), TypeName: "[]github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1.Type5"},
+							{Name: "Field1", Doc: "Some doc.", Anonymous: false, JSONRequired: true, JSONProperty: "Field1", Type: types.Typ[types.Int], TypeName: "int", Position: position(file1, 7, 2)},
+							{Name: "Field2", Doc: "", Anonymous: false, JSONRequired: true, JSONProperty: "f2", Type: types.Typ[types.String], TypeName: "string", Position: position(file1, 8, 2)},
+							{Name: "Field4", Doc: "Even more doc.", Anonymous: false, JSONRequired: false, JSONProperty: "", Type: types.NewSlice(types.Typ[types.String]), TypeName: "[]string", Position: position(file1, 12, 2)},
+							{Name: "Field5", Doc: "And some\nmore doc.", Anonymous: false, JSONRequired: false, JSONProperty: "f5", Type: types.NewMap(types.Typ[types.String], types.Typ[types.Bool]), TypeName: "map[string]bool", Position: position(file1, 15, 2)},
+							{Name: "Type5", Doc: "", Anonymous: true, JSONRequired: false, JSONProperty: "", Type: typeFromPackage(pkgs[0], "Type1", "Type5"), TypeName: "github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1.Type5", Position: position(file1, 16, 2)},
+							{Name: "Type5s", Doc: "", JSONRequired: false, JSONProperty: "t5s", Type: typeFromPackage(pkgs[0], "Type1", "Type5s"), TypeName: "[]github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1.Type5", Position: position(file1, 17, 2)},
 						},
-						Doc:            "Type1 is a normal type\nwith a single field and a description.",
+						Doc:      "Type1 is a normal type\nwith a single field and a description.",
+						Position: position(file1, 5, 6),
 					},
 					{
 						Name:    "Type5",
 						Package: "github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1",
 						Fields: []Field{
-							{Name: "Type5Field", Doc: "Something.", Anonymous: false, JSONRequired: true, JSONProperty: "t5", Type: types.Typ[types.Uint32], TypeName: "uint32"},
-							{Name: "Type5Field2", Doc: "Something else.", Anonymous: false, JSONRequired: true, JSONProperty: "t6", Type: types.NewSlice(types.Typ[types.Uint32]), TypeName: "[]uint32"},
+							{Name: "Type5Field", Doc: "Something.", Anonymous: false, JSONRequired: true, JSONProperty: "t5", Type: types.Typ[types.Uint32], TypeName: "uint32", Position: position(file1, 40, 2)},
+							{Name: "Type5Field2", Doc: "Something else.", Anonymous: false, JSONRequired: true, JSONProperty: "t6", Type: types.NewSlice(types.Typ[types.Uint32]), TypeName: "[]uint32", Position: position(file1, 43, 2)},
 						},
-						Doc:            "",
+						Doc:      "",
+						Position: position(file1, 38, 6),
 					},
 				},
 			},
 		}))
 	})
 })
+
+var _ = Describe("Sensitive fields", func() {
+	It("flags markers, tags and registered types", func() {
+		RegisterSensitiveType("github.com/jimmidyson/prettyconf/pkg/loader/testdata/sensitive.Password")
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/sensitive"}, logger)
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkgs).To(HaveLen(1))
+		Expect(pkgs[0].Types).To(HaveLen(1))
+
+		sensitive := map[string]bool{}
+		for _, f := range pkgs[0].Types[0].Fields {
+			sensitive[f.Name] = f.Sensitive
+		}
+		Expect(sensitive).To(Equal(map[string]bool{
+			"User":      false,
+			"Token":     true,
+			"Key":       true,
+			"Passwords": true,
+		}))
+		Expect(pkgs[0].Types[0].Fields[1].Doc).To(Equal("Token is marked by a doc marker."))
+		Expect(pkgs[0].Types[0].Fields[1].Markers).To(Equal(Markers{"sensitive": {""}}))
+	})
+})
+
+var _ = Describe("Presentation tags", func() {
+	It("loads prettyconf tag options", func() {
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/presentation"}, logger)
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkgs).To(HaveLen(1))
+		Expect(pkgs[0].Types).To(HaveLen(1))
+
+		fields := pkgs[0].Types[0].Fields
+		Expect(fields).To(HaveLen(4))
+		Expect(fields[0].Doc).To(Equal("The listen address. It must include a port."))
+		Expect(fields[0].Summary).To(Equal("The listen address."))
+		Expect(fields[0].Order).To(Equal(1))
+		Expect(fields[0].Section).To(Equal("Server"))
+		Expect(fields[0].Example).To(Equal("localhost:8080"))
+		Expect(fields[0].Hidden).To(BeFalse())
+		Expect(fields[1].Hidden).To(BeTrue())
+		Expect(fields[2].Order).To(Equal(3))
+		Expect(fields[3].Enum).To(Equal([]string{"debug", "info"}))
+	})
+})
+
+var _ = Describe("Package patterns and type filters", func() {
+	typeNames := func(pkgs []Package) []string {
+		var names []string
+		for _, pkg := range pkgs {
+			for _, t := range pkg.Types {
+				names = append(names, t.Name)
+			}
+		}
+		return names
+	}
+
+	It("expands package patterns", func() {
+		loader := New([]string{"./testdata/roots", "github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1"}, logger)
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		paths := make([]string, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			paths = append(paths, pkg.Path)
+		}
+		Expect(paths).To(ConsistOf(
+			"github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1",
+			"github.com/jimmidyson/prettyconf/pkg/loader/testdata/roots",
+		))
+	})
+
+	It("expands package patterns relative to a directory", func() {
+		loader := New([]string{"./roots"}, logger, WithDir("testdata"))
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkgs).To(HaveLen(1))
+		Expect(pkgs[0].Path).To(Equal("github.com/jimmidyson/prettyconf/pkg/loader/testdata/roots"))
+	})
+
+	It("errors for patterns matching no packages", func() {
+		loader := New([]string{"./testdata/unknown/..."}, logger)
+		_, err := loader.Load()
+		Expect(err).To(HaveOccurred())
+		Expect(IsSourceError(err)).To(BeTrue())
+	})
+
+	It("includes and excludes types by name", func() {
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/roots"}, logger,
+			WithIncludeTypes("*Config"), WithExcludeTypes("Client*"))
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typeNames(pkgs)).To(Equal([]string{"ServerConfig", "TLSOptions"}))
+	})
+
+	It("keeps excluded types referenced by included types", func() {
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/roots"}, logger,
+			WithIncludeTypes("Server*"), WithExcludeTypes("TLS*"))
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typeNames(pkgs)).To(Equal([]string{"ServerConfig", "TLSOptions"}))
+		rootType, found := FindType(pkgs, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/roots", "ServerConfig")
+		Expect(found).To(BeTrue())
+		var paths []string
+		Walk(rootType, pkgs, nil, func(field *WalkedField) bool {
+			paths = append(paths, field.Path)
+			return true
+		})
+		Expect(paths).To(Equal([]string{"listen", "tls", "tls.caFile"}))
+	})
+
+	It("loads only types with the root marker", func() {
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/roots"}, logger,
+			WithRootMarker("prettyconf:root"))
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typeNames(pkgs)).To(Equal([]string{"ServerConfig", "ClientConfig", "TLSOptions"}))
+	})
+
+	It("errors for invalid type patterns", func() {
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/roots"}, logger,
+			WithIncludeTypes("["))
+		_, err := loader.Load()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Unions", func() {
+	var (
+		loader *ASTLoader
+		pkgs   []Package
+	)
+
+	BeforeEach(func() {
+		loader = New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/unions"}, logger)
+		var err error
+		pkgs, err = loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("loads discriminated unions", func() {
+		storage, found := FindType(pkgs, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/unions", "StorageConfig")
+		Expect(found).To(BeTrue())
+		Expect(storage.Union).To(Equal(&Union{
+			Discriminator: "type",
+			Members: []UnionMember{
+				{JSONProperty: "s3", Value: "s3"},
+				{JSONProperty: "gcs", Value: "gcs"},
+			},
+		}))
+	})
+
+	It("loads unions without markers on members", func() {
+		cache, found := FindType(pkgs, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/unions", "CacheConfig")
+		Expect(found).To(BeTrue())
+		Expect(cache.Union).To(Equal(&Union{
+			Members: []UnionMember{
+				{JSONProperty: "memory", Value: "memory"},
+				{JSONProperty: "disk", Value: "disk"},
+				{JSONProperty: "backend", Value: "backend"},
+			},
+		}))
+	})
+
+	It("lists implementations of interfaces", func() {
+		cache, _ := FindType(pkgs, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/unions", "CacheConfig")
+		implementations, err := loader.Implementations(cache.Fields[2].Type)
+		Expect(err).NotTo(HaveOccurred())
+		names := make([]string, 0, len(implementations))
+		for _, t := range implementations {
+			names = append(names, t.Name)
+		}
+		Expect(names).To(Equal([]string{"GCSBackend", "S3Backend"}))
+	})
+
+	It("lists no implementations of empty interfaces", func() {
+		plugin, _ := FindType(pkgs, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/unions", "PluginConfig")
+		implementations, err := loader.Implementations(plugin.Fields[0].Type)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(implementations).To(BeEmpty())
+	})
+
+	It("errors listing implementations of non-interfaces", func() {
+		cache, _ := FindType(pkgs, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/unions", "CacheConfig")
+		_, err := loader.Implementations(cache.Fields[0].Type)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Deprecations", func() {
+	It("loads deprecated types and fields", func() {
+		loader := New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/deprecated"}, logger)
+		pkgs, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkgs).To(HaveLen(1))
+		Expect(pkgs[0].Types).To(HaveLen(1))
+
+		oldConfig := pkgs[0].Types[0]
+		Expect(oldConfig.Doc).To(Equal("OldConfig is an old config."))
+		Expect(oldConfig.Deprecated).To(Equal(&Deprecation{Message: "OldConfig is no longer read."}))
+		Expect(oldConfig.Fields[0].Doc).To(Equal("Address is the address to listen on."))
+		Expect(oldConfig.Fields[0].Deprecated).To(Equal(&Deprecation{Message: "Use [Config.Listen] instead.", Replacement: "Config.Listen"}))
+		Expect(oldConfig.Fields[1].Deprecated).To(BeNil())
+	})
+})
+
+var _ = Describe("ValidateStructTag", func() {
+	It("accepts well formed tags", func() {
+		Expect(ValidateStructTag(`json:"name,omitempty" prettyconf:"secret"`)).To(Succeed())
+		Expect(ValidateStructTag("")).To(Succeed())
+	})
+
+	It("rejects tags that parsing stops at", func() {
+		tags, err := ParseStructTags(`json:"name" prettyconf:secret`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tags).To(Equal(StructTags{{Name: "json", Value: "name"}}))
+		Expect(ValidateStructTag(`json:"name" prettyconf:secret`)).To(MatchError("malformed struct tag at `prettyconf:secret`"))
+		Expect(ValidateStructTag(`json: "name"`)).NotTo(Succeed())
+		Expect(ValidateStructTag(`json:"name`)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,59 @@
+package loader
+
+import (
+	"go/types"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// LoadFor loads the package of the type of conf, which may be a pointer, returning the loaded type
+// of conf and all loaded packages.
+func LoadFor(conf interface{}, logger logr.Logger) (Type, []Package, error) {
+	confType := reflect.TypeOf(conf)
+	for confType.Kind() == reflect.Ptr {
+		confType = confType.Elem()
+	}
+	confTypePkgPath := confType.PkgPath()
+
+	packages, err := New([]string{confTypePkgPath}, logger).Load()
+	if err != nil {
+		return Type{}, nil, errors.Wrapf(err, "failed to parse package %s", confTypePkgPath)
+	}
+	rootType, found := FindType(packages, confTypePkgPath, confType.Name())
+	if !found {
+		return Type{}, nil, errors.Errorf("type %s.%s could not be found", confTypePkgPath, confType.Name())
+	}
+	return rootType, packages, nil
+}
+
+// FindType returns the loaded type name in the package with path pkgPath.
+func FindType(packages []Package, pkgPath, name string) (Type, bool) {
+	for _, pkg := range packages {
+		if pkg.Path != pkgPath {
+			continue
+		}
+		for _, t := range pkg.Types {
+			if t.Name == name {
+				return t, true
+			}
+		}
+	}
+	return Type{}, false
+}
+
+// FindNamedType returns the loaded type for the named type t, dereferencing pointers.
+func FindNamedType(packages []Package, t types.Type) (Type, bool) {
+	switch typ := t.(type) {
+	case *types.Pointer:
+		return FindNamedType(packages, typ.Elem())
+	case *types.Named:
+		if typ.Obj().Pkg() == nil {
+			return Type{}, false
+		}
+		return FindType(packages, typ.Obj().Pkg().Path(), typ.Obj().Name())
+	default:
+		return Type{}, false
+	}
+}
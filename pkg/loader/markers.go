@@ -0,0 +1,99 @@
+package loader
+
+import (
+	"strings"
+)
+
+// OrderMarker is the doc comment marker that sets the explicit order of a field, e.g. `+order=2`.
+const OrderMarker = "order"
+
+// EnumMarker is the doc comment marker that lists the allowed values of a field, e.g. `+enum=a,b`.
+// Values may be separated by commas or semicolons.
+const EnumMarker = "enum"
+
+// RenamedFromMarker is the doc comment marker that lists the previous keys of a field in the same
+// mapping, e.g. `+renamedFrom=listenAddr`. Keys may be separated by commas.
+const RenamedFromMarker = "renamedFrom"
+
+// MovedFromMarker is the doc comment marker that lists the previous dot separated key paths of a
+// field from the root of the config, e.g. `+movedFrom=server.tls.cert`. Paths may be separated by
+// commas.
+const MovedFromMarker = "movedFrom"
+
+// DefaultMarker is the doc comment marker that documents the default value of a field, e.g.
+// `+default=8080` or `+default="info"`. The value is read as JSON, or as a string if it is not
+// valid JSON.
+const DefaultMarker = "default"
+
+// Markers holds the values of the `+name` and `+name=value` marker lines found in a doc comment,
+// keyed by name in the order they appear. Markers without a value are stored with an empty value.
+type Markers map[string][]string
+
+// Has returns true if the marker name is present.
+func (m Markers) Has(name string) bool {
+	_, ok := m[name]
+	return ok
+}
+
+// Get returns the value of the marker name and whether it is present. If the marker appears more
+// than once the last value is returned.
+func (m Markers) Get(name string) (string, bool) {
+	values, ok := m[name]
+	if !ok || len(values) == 0 {
+		return "", ok
+	}
+	return values[len(values)-1], true
+}
+
+// List returns the comma separated values of every occurrence of the marker name, in the order
+// they appear, or nil if it is not present.
+func (m Markers) List(name string) []string {
+	var values []string
+	for _, value := range m[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// ExtractMarkers splits a doc comment into its prose and its markers. Marker lines are removed
+// from the returned doc. If there are no markers the returned Markers is nil.
+func ExtractMarkers(doc string) (string, Markers) {
+	var markers Markers
+	lines := strings.Split(doc, "\n")
+	docLines := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "+") || len(trimmed) == 1 {
+			docLines = append(docLines, line)
+			continue
+		}
+		if markers == nil {
+			markers = Markers{}
+		}
+		name, value := trimmed[1:], ""
+		if idx := strings.Index(name, "="); idx > -1 {
+			name, value = name[:idx], name[idx+1:]
+		}
+		name = strings.TrimSpace(name)
+		markers[name] = append(markers[name], strings.TrimSpace(value))
+	}
+	return strings.TrimSpace(strings.Join(docLines, "\n")), markers
+}
+
+func enumValues(markers Markers) []string {
+	enumMarker, ok := markers.Get(EnumMarker)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, value := range strings.FieldsFunc(enumMarker, func(r rune) bool { return r == ',' || r == ';' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
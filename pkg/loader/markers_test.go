@@ -0,0 +1,39 @@
+package loader_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+var _ = Describe("ExtractMarkers", func() {
+	DescribeTable("extracted markers",
+		func(doc, expectedDoc string, expectedMarkers Markers) {
+			extractedDoc, markers := ExtractMarkers(doc)
+			Expect(extractedDoc).To(Equal(expectedDoc))
+			Expect(markers).To(Equal(expectedMarkers))
+		},
+		Entry("no markers", "Some doc.\n", "Some doc.", nil),
+		Entry("single marker", "Some doc.\n+optional\n", "Some doc.", Markers{"optional": {""}}),
+		Entry("valued markers", "+order=2\nSome doc.\n  +enum = a,b\n", "Some doc.", Markers{"order": {"2"}, "enum": {"a,b"}}),
+		Entry("repeated markers", "+renamedFrom=a\n+renamedFrom=b\n", "", Markers{"renamedFrom": {"a", "b"}}),
+		Entry("lone plus", "Some doc.\n+\n", "Some doc.\n+", nil),
+	)
+})
+
+var _ = Describe("Markers", func() {
+	It("splits list values", func() {
+		markers := Markers{RenamedFromMarker: {"addr, listen,", "host"}, MovedFromMarker: {""}}
+		Expect(markers.List(RenamedFromMarker)).To(Equal([]string{"addr", "listen", "host"}))
+		Expect(markers.List(MovedFromMarker)).To(BeEmpty())
+		Expect(markers.List("missing")).To(BeNil())
+	})
+
+	It("gets the last value of repeated markers", func() {
+		value, ok := Markers{OrderMarker: {"1", "2"}}.Get(OrderMarker)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("2"))
+	})
+})
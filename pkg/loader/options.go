@@ -0,0 +1,119 @@
+package loader
+
+import (
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// Option configures an ASTLoader.
+type Option func(*ASTLoader)
+
+// WithIncludeTypes only loads types whose names match at least one of the glob patterns, using
+// the syntax of path.Match, and the types they reference.
+func WithIncludeTypes(patterns ...string) Option {
+	return func(l *ASTLoader) {
+		l.includeTypes = append(l.includeTypes, patterns...)
+	}
+}
+
+// WithExcludeTypes skips types whose names match any of the glob patterns, using the syntax of
+// path.Match, unless they are referenced by a loaded type. Exclusions take precedence over
+// inclusions.
+func WithExcludeTypes(patterns ...string) Option {
+	return func(l *ASTLoader) {
+		l.excludeTypes = append(l.excludeTypes, patterns...)
+	}
+}
+
+// WithRootMarker only loads types whose doc comment carries the marker, e.g. `prettyconf:root`
+// for types documented with `+prettyconf:root`, and the types they reference.
+func WithRootMarker(marker string) Option {
+	return func(l *ASTLoader) {
+		l.rootMarker = marker
+	}
+}
+
+// filterTypes returns packages with only the root types chosen by the type filters and the types
+// they reference. Packages left without types are removed.
+func (l *ASTLoader) filterTypes(packages []Package) ([]Package, error) {
+	if l.rootMarker == "" && len(l.includeTypes) == 0 && len(l.excludeTypes) == 0 {
+		return packages, nil
+	}
+
+	kept := map[string]bool{}
+	for _, pkg := range packages {
+		for _, t := range pkg.Types {
+			include, err := l.includeType(t)
+			if err != nil {
+				return nil, err
+			}
+			if !include || kept[t.Package+"."+t.Name] {
+				continue
+			}
+			kept[t.Package+"."+t.Name] = true
+			Walk(t, packages, nil, func(field *WalkedField) bool {
+				if field.Elem == nil || kept[field.Elem.Package+"."+field.Elem.Name] {
+					return false
+				}
+				kept[field.Elem.Package+"."+field.Elem.Name] = true
+				return true
+			})
+		}
+	}
+
+	filtered := make([]Package, 0, len(packages))
+	for _, pkg := range packages {
+		keptTypes := make([]Type, 0, len(pkg.Types))
+		for _, t := range pkg.Types {
+			if !kept[t.Package+"."+t.Name] {
+				l.logger.V(5).Info("skipping filtered type", "package", pkg.Path, "type", t.Name)
+				continue
+			}
+			keptTypes = append(keptTypes, t)
+		}
+		if len(keptTypes) == 0 {
+			l.logger.V(5).Info("skipping package - no types left after filtering", "package", pkg.Path)
+			continue
+		}
+		pkg.Types = keptTypes
+		filtered = append(filtered, pkg)
+	}
+	return filtered, nil
+}
+
+func (l *ASTLoader) includeType(t Type) (bool, error) {
+	if l.rootMarker != "" && !t.Markers.Has(l.rootMarker) {
+		return false, nil
+	}
+	for _, pattern := range l.excludeTypes {
+		matched, err := path.Match(pattern, t.Name)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid exclude pattern %q", pattern)
+		}
+		if matched {
+			return false, nil
+		}
+	}
+	if len(l.includeTypes) == 0 {
+		return true, nil
+	}
+	for _, pattern := range l.includeTypes {
+		matched, err := path.Match(pattern, t.Name)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid include pattern %q", pattern)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// WithDir loads packages relative to the directory dir instead of the current directory, e.g. to
+// load a package from another checkout of the same module.
+func WithDir(dir string) Option {
+	return func(l *ASTLoader) {
+		l.dir = dir
+	}
+}
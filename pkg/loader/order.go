@@ -0,0 +1,64 @@
+package loader
+
+import (
+	"sort"
+)
+
+// FieldOrder is a strategy for ordering the fields of a type in generated output.
+type FieldOrder int
+
+const (
+	// ExplicitOrder sorts fields by their explicit order, set with the `+order=N` marker or the
+	// `order=N` prettyconf tag option. Fields without an explicit order, or with equal orders, keep
+	// their declaration order.
+	ExplicitOrder FieldOrder = iota
+	// SourceOrder keeps fields in their declaration order.
+	SourceOrder
+	// AlphabeticalOrder sorts fields by their JSON property name.
+	AlphabeticalOrder
+	// RequiredFirstOrder puts required fields before optional fields, otherwise keeping their
+	// declaration order.
+	RequiredFirstOrder
+)
+
+// String returns the name of the strategy.
+func (o FieldOrder) String() string {
+	switch o {
+	case ExplicitOrder:
+		return "explicit"
+	case SourceOrder:
+		return "source"
+	case AlphabeticalOrder:
+		return "alphabetical"
+	case RequiredFirstOrder:
+		return "required-first"
+	default:
+		return "unknown"
+	}
+}
+
+// SortFields returns the fields sorted by the order strategy. Hidden fields are kept, so callers
+// that document fields must leave them out. The passed in fields are not modified.
+func SortFields(fields []Field, order FieldOrder) []Field {
+	sorted := append([]Field(nil), fields...)
+
+	var less func(i, j int) bool
+	switch order {
+	case ExplicitOrder:
+		less = func(i, j int) bool {
+			return sorted[i].Order < sorted[j].Order
+		}
+	case AlphabeticalOrder:
+		less = func(i, j int) bool {
+			return sorted[i].JSONProperty < sorted[j].JSONProperty
+		}
+	case RequiredFirstOrder:
+		less = func(i, j int) bool {
+			return sorted[i].JSONRequired && !sorted[j].JSONRequired
+		}
+	default:
+		return sorted
+	}
+	sort.SliceStable(sorted, less)
+	return sorted
+}
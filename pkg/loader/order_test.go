@@ -0,0 +1,34 @@
+package loader_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+var unsortedFields = []Field{
+	{Name: "C", JSONProperty: "c", JSONRequired: false},
+	{Name: "A", JSONProperty: "a", JSONRequired: true, Order: 2},
+	{Name: "Hidden", JSONProperty: "hidden", Hidden: true},
+	{Name: "D", JSONProperty: "d", JSONRequired: false, Order: -1},
+	{Name: "B", JSONProperty: "b", JSONRequired: true},
+}
+
+var _ = Describe("SortFields", func() {
+	DescribeTable("sorted fields",
+		func(order FieldOrder, expected []string) {
+			sorted := SortFields(unsortedFields, order)
+			names := make([]string, 0, len(sorted))
+			for _, f := range sorted {
+				names = append(names, f.JSONProperty)
+			}
+			Expect(names).To(Equal(expected))
+		},
+		Entry("explicit", ExplicitOrder, []string{"d", "c", "hidden", "b", "a"}),
+		Entry("source", SourceOrder, []string{"c", "a", "hidden", "d", "b"}),
+		Entry("alphabetical", AlphabeticalOrder, []string{"a", "b", "c", "d", "hidden"}),
+		Entry("required first", RequiredFirstOrder, []string{"a", "b", "c", "hidden", "d"}),
+	)
+})
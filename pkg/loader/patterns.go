@@ -0,0 +1,45 @@
+package loader
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// isPattern returns true if pkg is a package pattern, such as `./...`, rather than an import path.
+func isPattern(pkg string) bool {
+	return strings.Contains(pkg, "...") || pkg == "." || pkg == ".." ||
+		strings.HasPrefix(pkg, "./") || strings.HasPrefix(pkg, "../")
+}
+
+// expandPatterns resolves any package patterns in requested, relative to dir, to the import paths
+// they match, leaving plain import paths untouched.
+func expandPatterns(requested []string, dir string) ([]string, error) {
+	var (
+		importPaths []string
+		patterns    []string
+	)
+	for _, pkg := range requested {
+		if isPattern(pkg) {
+			patterns = append(patterns, pkg)
+			continue
+		}
+		importPaths = append(importPaths, pkg)
+	}
+	if len(patterns) == 0 {
+		return importPaths, nil
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: dir}, patterns...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot expand package patterns %v", patterns)
+	}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, errors.Errorf("cannot expand package patterns %v: %v", patterns, pkg.Errors[0])
+		}
+		importPaths = append(importPaths, pkg.PkgPath)
+	}
+	return importPaths, nil
+}
@@ -0,0 +1,34 @@
+package loader
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// Position is the position of a type or field declaration in its source file. Types built by
+// FromReflect have no position.
+type Position struct {
+	// Filename is the path of the source file as loaded, which is absolute unless the package was
+	// loaded from relative paths.
+	Filename string
+	Line     int
+	Column   int
+}
+
+// IsValid returns true if the position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position as `file:line:column`, or `-` if it is not known.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+func newPosition(fset *token.FileSet, pos token.Pos) Position {
+	position := fset.Position(pos)
+	return Position{Filename: position.Filename, Line: position.Line, Column: position.Column}
+}
@@ -0,0 +1,99 @@
+package loader
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PrettyconfTagName is the name of the struct tag used to control how a field is presented.
+const PrettyconfTagName = "prettyconf"
+
+// PrettyconfTag is the parsed value of a `prettyconf` struct tag. The tag value is a comma
+// separated list of options, for example:
+//
+//	`prettyconf:"secret,order=1,section=Server,example=localhost:8080,summary=The listen address."`
+//
+// Supported options are `-` or `hidden`, `secret`, `order=N`, `section=name`, `example=value`,
+// `summary=text` and `env=NAME`. A literal comma in an option value is written as `\,`.
+type PrettyconfTag struct {
+	Hidden  bool
+	Secret  bool
+	Order   int
+	Section string
+	Example string
+	Summary string
+	Env     string
+}
+
+// ParsePrettyconfTag parses the value of a `prettyconf` struct tag.
+func ParsePrettyconfTag(value string) (PrettyconfTag, error) {
+	var tag PrettyconfTag
+	for _, option := range splitTagOptions(value) {
+		name, optionValue := option, ""
+		if idx := strings.Index(option, "="); idx > -1 {
+			name, optionValue = option[:idx], option[idx+1:]
+		}
+		switch strings.TrimSpace(name) {
+		case "":
+			continue
+		case "-", "hidden":
+			tag.Hidden = true
+		case "secret":
+			tag.Secret = true
+		case "order":
+			order, err := strconv.Atoi(strings.TrimSpace(optionValue))
+			if err != nil {
+				return PrettyconfTag{}, errors.Wrapf(err, "invalid order %q", optionValue)
+			}
+			tag.Order = order
+		case "section":
+			tag.Section = optionValue
+		case "example":
+			tag.Example = optionValue
+		case "summary":
+			tag.Summary = optionValue
+		case "env":
+			tag.Env = strings.TrimSpace(optionValue)
+		default:
+			return PrettyconfTag{}, errors.Errorf("unknown %s tag option %q", PrettyconfTagName, name)
+		}
+	}
+	return tag, nil
+}
+
+func splitTagOptions(value string) []string {
+	var (
+		options []string
+		current strings.Builder
+	)
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value) && value[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case value[i] == ',':
+			options = append(options, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+	return append(options, current.String())
+}
+
+// overrideSummary replaces the first sentence of doc with summary.
+func overrideSummary(doc, summary string) string {
+	if summary == "" {
+		return doc
+	}
+	end := len(doc)
+	for i := 0; i < len(doc); i++ {
+		if doc[i] == '.' && (i+1 == len(doc) || doc[i+1] == ' ' || doc[i+1] == '\n') {
+			end = i + 1
+			break
+		}
+	}
+	return strings.TrimSpace(summary + doc[end:])
+}
@@ -0,0 +1,37 @@
+package loader_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+var _ = Describe("ParsePrettyconfTag", func() {
+	DescribeTable("parsed tags",
+		func(value string, expected PrettyconfTag) {
+			tag, err := ParsePrettyconfTag(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tag).To(Equal(expected))
+		},
+		Entry("empty", "", PrettyconfTag{}),
+		Entry("hidden", "-", PrettyconfTag{Hidden: true}),
+		Entry("hidden by name", "hidden", PrettyconfTag{Hidden: true}),
+		Entry("secret", "secret", PrettyconfTag{Secret: true}),
+		Entry("all options",
+			"secret,order=-2,section=Server,example=localhost:8080,summary=The address.",
+			PrettyconfTag{Secret: true, Order: -2, Section: "Server", Example: "localhost:8080", Summary: "The address."}),
+		Entry("env", "env=APP_PORT", PrettyconfTag{Env: "APP_PORT"}),
+		Entry("escaped commas", `example=a\,b,summary=One\, two.`, PrettyconfTag{Example: "a,b", Summary: "One, two."}),
+	)
+
+	DescribeTable("invalid tags",
+		func(value string) {
+			_, err := ParsePrettyconfTag(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("invalid order", "order=first"),
+		Entry("unknown option", "colour=red"),
+	)
+})
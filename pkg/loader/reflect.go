@@ -0,0 +1,220 @@
+package loader
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// FromReflect builds the packages and types reachable from the struct type t using reflection
+// alone, for use when the source of its package is not available. The result has no docs or doc
+// comment markers, but struct tags and registered sensitive types are honoured.
+func FromReflect(t reflect.Type) ([]Package, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return nil, errors.Errorf("%s is not a named struct type", t)
+	}
+
+	c := &reflectConverter{
+		packages: map[string]*types.Package{},
+		named:    map[reflect.Type]*types.Named{},
+	}
+	if _, err := c.convert(t); err != nil {
+		return nil, err
+	}
+	return c.loaded, nil
+}
+
+type reflectConverter struct {
+	packages map[string]*types.Package
+	named    map[reflect.Type]*types.Named
+	loaded   []Package
+}
+
+func (c *reflectConverter) convert(t reflect.Type) (types.Type, error) {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return c.convertNamed(t)
+	}
+	return c.convertUnderlying(t)
+}
+
+func (c *reflectConverter) convertNamed(t reflect.Type) (types.Type, error) {
+	if named, ok := c.named[t]; ok {
+		return named, nil
+	}
+	pkg, ok := c.packages[t.PkgPath()]
+	if !ok {
+		pkg = types.NewPackage(t.PkgPath(), packageName(t.PkgPath()))
+		c.packages[t.PkgPath()] = pkg
+	}
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, t.Name(), nil), nil, nil)
+	// Register before converting the underlying type so recursive types terminate.
+	c.named[t] = named
+
+	underlying, err := c.convertUnderlying(t)
+	if err != nil {
+		return nil, err
+	}
+	named.SetUnderlying(underlying.Underlying())
+
+	if t.Kind() == reflect.Struct {
+		if err := c.addStructType(t, underlying.(*types.Struct)); err != nil {
+			return nil, err
+		}
+	}
+	return named, nil
+}
+
+func (c *reflectConverter) convertUnderlying(t reflect.Type) (types.Type, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return types.Typ[types.Bool], nil
+	case reflect.Int:
+		return types.Typ[types.Int], nil
+	case reflect.Int8:
+		return types.Typ[types.Int8], nil
+	case reflect.Int16:
+		return types.Typ[types.Int16], nil
+	case reflect.Int32:
+		return types.Typ[types.Int32], nil
+	case reflect.Int64:
+		return types.Typ[types.Int64], nil
+	case reflect.Uint:
+		return types.Typ[types.Uint], nil
+	case reflect.Uint8:
+		return types.Typ[types.Uint8], nil
+	case reflect.Uint16:
+		return types.Typ[types.Uint16], nil
+	case reflect.Uint32:
+		return types.Typ[types.Uint32], nil
+	case reflect.Uint64:
+		return types.Typ[types.Uint64], nil
+	case reflect.Uintptr:
+		return types.Typ[types.Uintptr], nil
+	case reflect.Float32:
+		return types.Typ[types.Float32], nil
+	case reflect.Float64:
+		return types.Typ[types.Float64], nil
+	case reflect.Complex64:
+		return types.Typ[types.Complex64], nil
+	case reflect.Complex128:
+		return types.Typ[types.Complex128], nil
+	case reflect.String:
+		return types.Typ[types.String], nil
+	case reflect.Ptr:
+		elem, err := c.convert(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return types.NewPointer(elem), nil
+	case reflect.Slice:
+		elem, err := c.convert(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return types.NewSlice(elem), nil
+	case reflect.Array:
+		elem, err := c.convert(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return types.NewArray(elem, int64(t.Len())), nil
+	case reflect.Map:
+		key, err := c.convert(t.Key())
+		if err != nil {
+			return nil, err
+		}
+		elem, err := c.convert(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMap(key, elem), nil
+	case reflect.Interface:
+		// Method signatures are not needed to print values, so all interfaces are empty.
+		return types.NewInterfaceType(nil, nil).Complete(), nil
+	case reflect.Struct:
+		return c.convertStruct(t)
+	default:
+		return nil, errors.Errorf("unsupported type %s", t)
+	}
+}
+
+func (c *reflectConverter) convertStruct(t reflect.Type) (*types.Struct, error) {
+	var (
+		fields []*types.Var
+		tags   []string
+	)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" || isUnserializableKind(sf.Type.Kind()) {
+			continue
+		}
+		fieldType, err := c.convert(sf.Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert field %s.%s", t, sf.Name)
+		}
+		fields = append(fields, types.NewField(token.NoPos, nil, sf.Name, fieldType, sf.Anonymous))
+		tags = append(tags, string(sf.Tag))
+	}
+	return types.NewStruct(fields, tags), nil
+}
+
+func (c *reflectConverter) addStructType(t reflect.Type, structType *types.Struct) error {
+	structFields := make([]Field, 0, structType.NumFields())
+	for i := 0; i < structType.NumFields(); i++ {
+		fld := structType.Field(i)
+		tags, err := ParseFieldTags(fld.Name(), structType.Tag(i))
+		if err != nil {
+			return err
+		}
+		if tags.JSONProperty == "-" {
+			continue
+		}
+		structFields = append(structFields, Field{
+			Name:         fld.Name(),
+			Doc:          tags.Prettyconf.Summary,
+			Type:         fld.Type(),
+			TypeName:     typeName(fld.Type()),
+			Anonymous:    fld.Anonymous(),
+			JSONProperty: tags.JSONProperty,
+			JSONRequired: tags.Required,
+			Sensitive:    tags.Prettyconf.Secret || isSensitiveType(fld.Type()),
+			Hidden:       tags.Prettyconf.Hidden,
+			Order:        tags.Prettyconf.Order,
+			Section:      tags.Prettyconf.Section,
+			Example:      tags.Prettyconf.Example,
+			Summary:      tags.Prettyconf.Summary,
+			Env:          tags.Prettyconf.Env,
+		})
+	}
+	if len(structFields) == 0 {
+		return nil
+	}
+
+	pkgType := Type{Name: t.Name(), Package: t.PkgPath(), Fields: structFields}
+	for i := range c.loaded {
+		if c.loaded[i].Path == t.PkgPath() {
+			c.loaded[i].Types = append(c.loaded[i].Types, pkgType)
+			return nil
+		}
+	}
+	c.loaded = append(c.loaded, Package{Path: t.PkgPath(), Types: []Type{pkgType}})
+	return nil
+}
+
+func isUnserializableKind(kind reflect.Kind) bool {
+	return kind == reflect.Func || kind == reflect.Chan || kind == reflect.UnsafePointer
+}
+
+func packageName(pkgPath string) string {
+	for i := len(pkgPath) - 1; i >= 0; i-- {
+		if pkgPath[i] == '/' {
+			return pkgPath[i+1:]
+		}
+	}
+	return pkgPath
+}
@@ -0,0 +1,59 @@
+package loader_test
+
+import (
+	"go/types"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1"
+	"github.com/jimmidyson/prettyconf/pkg/loader/testdata/presentation"
+)
+
+var _ = Describe("FromReflect", func() {
+	It("errors for non-struct types", func() {
+		_, err := FromReflect(reflect.TypeOf(""))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("builds types from reflection", func() {
+		pkgs, err := FromReflect(reflect.TypeOf(&pkg1.Type1{}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkgs).To(HaveLen(1))
+		Expect(pkgs[0].Path).To(Equal("github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1"))
+
+		type1, found := FindType(pkgs, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1", "Type1")
+		Expect(found).To(BeTrue())
+		Expect(type1.Doc).To(BeEmpty())
+
+		summaries := make([]string, 0, len(type1.Fields))
+		for _, f := range type1.Fields {
+			summaries = append(summaries, f.JSONProperty+" "+f.TypeName)
+		}
+		Expect(summaries).To(Equal([]string{
+			"Field1 int",
+			"f2 string",
+			" []string",
+			"f5 map[string]bool",
+			" github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1.Type5",
+			"t5s []github.com/jimmidyson/prettyconf/pkg/loader/testdata/pkg1.Type5",
+		}))
+		Expect(type1.Fields[1].JSONRequired).To(BeTrue())
+		Expect(type1.Fields[3].JSONRequired).To(BeFalse())
+		Expect(type1.Fields[3].Type).To(Equal(types.NewMap(types.Typ[types.String], types.Typ[types.Bool])))
+
+		_, found = FindNamedType(pkgs, type1.Fields[5].Type.(*types.Slice).Elem())
+		Expect(found).To(BeTrue())
+	})
+
+	It("honours prettyconf tags", func() {
+		pkgs, err := FromReflect(reflect.TypeOf(presentation.Presentation{}))
+		Expect(err).NotTo(HaveOccurred())
+		fields := pkgs[0].Types[0].Fields
+		Expect(fields[0].Doc).To(Equal("The listen address."))
+		Expect(fields[0].Section).To(Equal("Server"))
+		Expect(fields[1].Hidden).To(BeTrue())
+	})
+})
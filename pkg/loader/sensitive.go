@@ -0,0 +1,49 @@
+package loader
+
+import (
+	"go/types"
+	"sync"
+)
+
+// SensitiveMarker is the doc comment marker that flags a field as sensitive.
+const SensitiveMarker = "sensitive"
+
+var (
+	sensitiveTypesMu sync.RWMutex
+	sensitiveTypes   = map[string]struct{}{}
+)
+
+// RegisterSensitiveType registers a named type, e.g. `github.com/org/repo/pkg.Password`, as
+// sensitive. Every field of that type, or of a pointer, slice, array or map of that type, is
+// loaded with Sensitive set.
+func RegisterSensitiveType(typeName string) {
+	sensitiveTypesMu.Lock()
+	defer sensitiveTypesMu.Unlock()
+	sensitiveTypes[typeName] = struct{}{}
+}
+
+func isSensitiveType(t types.Type) bool {
+	for {
+		switch typ := t.(type) {
+		case *types.Pointer:
+			t = typ.Elem()
+		case *types.Slice:
+			t = typ.Elem()
+		case *types.Array:
+			t = typ.Elem()
+		case *types.Map:
+			t = typ.Elem()
+		case *types.Named:
+			return isRegisteredSensitiveType(typeName(typ))
+		default:
+			return false
+		}
+	}
+}
+
+func isRegisteredSensitiveType(typeName string) bool {
+	sensitiveTypesMu.RLock()
+	defer sensitiveTypesMu.RUnlock()
+	_, ok := sensitiveTypes[typeName]
+	return ok
+}
@@ -0,0 +1,13 @@
+package deprecated
+
+// OldConfig is an old config.
+//
+// Deprecated: OldConfig is no longer read.
+type OldConfig struct {
+	// Address is the address to listen on.
+	//
+	// Deprecated: Use [Config.Listen] instead.
+	Address string `json:"address"`
+	// Listen is the address to listen on.
+	Listen string `json:"listen"`
+}
@@ -0,0 +1,8 @@
+package invalid
+
+// Config has an invalid marker.
+type Config struct {
+	// Name is the name.
+	// +order=first
+	Name string `json:"name"`
+}
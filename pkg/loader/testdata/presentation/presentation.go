@@ -0,0 +1,15 @@
+package presentation
+
+// Presentation holds fields controlled by the prettyconf tag.
+type Presentation struct {
+	// Address is the address to listen on. It must include a port.
+	Address string `json:"address" prettyconf:"order=1,section=Server,example=localhost:8080,summary=The listen address."`
+	// Internal is hidden from docs.
+	Internal string `json:"internal" prettyconf:"-"`
+	// Verbose is ordered by a marker.
+	// +order=3
+	Verbose bool `json:"verbose"`
+	// Level is the log level.
+	// +enum=debug; info
+	Level string `json:"level"`
+}
@@ -0,0 +1,23 @@
+package roots
+
+// ServerConfig is a config root.
+// +prettyconf:root
+type ServerConfig struct {
+	// Listen is the listen address.
+	Listen string `json:"listen"`
+	// TLS configures TLS.
+	TLS *TLSOptions `json:"tls,omitempty"`
+}
+
+// ClientConfig is a config root.
+// +prettyconf:root
+type ClientConfig struct {
+	// Server is the server address.
+	Server string `json:"server"`
+}
+
+// TLSOptions is not a config root but is referenced by one.
+type TLSOptions struct {
+	// CAFile is the CA file.
+	CAFile string `json:"caFile"`
+}
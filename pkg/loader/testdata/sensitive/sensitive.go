@@ -0,0 +1,17 @@
+package sensitive
+
+// Password is a registered sensitive type.
+type Password string
+
+// Credentials holds secret values.
+type Credentials struct {
+	// User is not secret.
+	User string `json:"user"`
+	// Token is marked by a doc marker.
+	// +sensitive
+	Token string `json:"token"`
+	// Key is marked by a struct tag.
+	Key string `json:"key" prettyconf:"secret"`
+	// Passwords holds registered sensitive types.
+	Passwords []Password `json:"passwords,omitempty"`
+}
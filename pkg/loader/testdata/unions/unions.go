@@ -0,0 +1,54 @@
+package unions
+
+// Backend is implemented by backend configs.
+type Backend interface {
+	backend()
+}
+
+// S3Backend configures S3.
+type S3Backend struct {
+	// Bucket is the bucket name.
+	Bucket string `json:"bucket"`
+}
+
+func (S3Backend) backend() {}
+
+// GCSBackend configures GCS.
+type GCSBackend struct {
+	// Bucket is the bucket name.
+	Bucket string `json:"bucket"`
+}
+
+func (*GCSBackend) backend() {}
+
+// StorageConfig configures one storage backend.
+// +union:discriminator=type
+type StorageConfig struct {
+	// Type selects the backend.
+	Type string `json:"type"`
+	// S3 configures S3 storage.
+	// +union:member=s3
+	S3 *S3Backend `json:"s3,omitempty"`
+	// GCS configures GCS storage.
+	// +union:member
+	GCS *GCSBackend `json:"gcs,omitempty"`
+	// Region is not a member.
+	Region string `json:"region,omitempty"`
+}
+
+// CacheConfig configures one cache.
+// +union
+type CacheConfig struct {
+	// Memory configures an in-memory cache.
+	Memory *S3Backend `json:"memory,omitempty"`
+	// Disk configures an on-disk cache.
+	Disk *S3Backend `json:"disk,omitempty"`
+	// Backend is the plugin backend.
+	Backend Backend `json:"backend"`
+}
+
+// PluginConfig configures a plugin.
+type PluginConfig struct {
+	// Options are plugin specific options.
+	Options interface{} `json:"options,omitempty"`
+}
@@ -0,0 +1,29 @@
+package walk
+
+// Config is a config.
+type Config struct {
+	// Name is the name.
+	Name string `json:"name"`
+	// Servers are the servers.
+	Servers []*Server `json:"servers"`
+	// Root is the root node.
+	Root Node `json:"root"`
+}
+
+// Server is a server.
+type Server struct {
+	// Labels are labels.
+	Labels map[string]Label `json:"labels"`
+}
+
+// Label is a label.
+type Label struct {
+	// Value is the value.
+	Value string `json:"value"`
+}
+
+// Node is a node of a tree.
+type Node struct {
+	// Children are the child nodes.
+	Children []Node `json:"children"`
+}
@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"go/types"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// UnionMarker is the type doc comment marker that declares a struct as a discriminated union
+	// of its member fields.
+	UnionMarker = "union"
+	// UnionDiscriminatorMarker is the type doc comment marker that names the JSON property of the
+	// union's discriminator field, e.g. `+union:discriminator=type`.
+	UnionDiscriminatorMarker = "union:discriminator"
+	// UnionMemberMarker is the field doc comment marker that declares a field as a union member,
+	// optionally with the discriminator value selecting it, e.g. `+union:member=s3`.
+	UnionMemberMarker = "union:member"
+)
+
+// Union describes a struct of which exactly one member field may be set.
+type Union struct {
+	// Discriminator is the JSON property of the field selecting the member, if any.
+	Discriminator string
+	Members       []UnionMember
+}
+
+// UnionMember is a member field of a union.
+type UnionMember struct {
+	// JSONProperty is the JSON property of the member field.
+	JSONProperty string
+	// Value is the discriminator value that selects the member.
+	Value string
+}
+
+// Values returns the discriminator values of all members.
+func (u *Union) Values() []string {
+	values := make([]string, 0, len(u.Members))
+	for _, m := range u.Members {
+		values = append(values, m.Value)
+	}
+	return values
+}
+
+// Member returns the member with the JSON property.
+func (u *Union) Member(jsonProperty string) (UnionMember, bool) {
+	for _, m := range u.Members {
+		if m.JSONProperty == jsonProperty {
+			return m, true
+		}
+	}
+	return UnionMember{}, false
+}
+
+// unionFromMarkers builds the union declared by type markers. Fields marked with
+// `+union:member` are the members; if there are none, all fields except the discriminator are.
+func unionFromMarkers(typeName string, markers Markers, fields []Field) (*Union, error) {
+	if !markers.Has(UnionMarker) && !markers.Has(UnionDiscriminatorMarker) {
+		return nil, nil
+	}
+	union := &Union{}
+	union.Discriminator, _ = markers.Get(UnionDiscriminatorMarker)
+
+	explicitMembers := false
+	for _, f := range fields {
+		if f.Markers.Has(UnionMemberMarker) {
+			explicitMembers = true
+			break
+		}
+	}
+
+	discriminatorFound := union.Discriminator == ""
+	for _, f := range fields {
+		if f.JSONProperty == union.Discriminator {
+			discriminatorFound = true
+			continue
+		}
+		value, marked := f.Markers.Get(UnionMemberMarker)
+		if explicitMembers && !marked {
+			continue
+		}
+		if value == "" {
+			value = f.JSONProperty
+		}
+		union.Members = append(union.Members, UnionMember{JSONProperty: f.JSONProperty, Value: value})
+	}
+	if !discriminatorFound {
+		return nil, errors.Errorf("union %s has no discriminator field %s", typeName, union.Discriminator)
+	}
+	return union, nil
+}
+
+// Implementations returns the loaded types that implement the interface iface, either directly or
+// through a pointer receiver, sorted by package path and name. Every type implements an empty
+// interface, so none are returned for one. Load must be called first.
+func (l *ASTLoader) Implementations(iface types.Type) ([]Type, error) {
+	if l.prog == nil {
+		return nil, errors.New("packages have not been loaded")
+	}
+	ifaceType, ok := iface.Underlying().(*types.Interface)
+	if !ok {
+		return nil, errors.Errorf("%s is not an interface", iface)
+	}
+	if ifaceType.NumMethods() == 0 {
+		return nil, nil
+	}
+
+	var implementations []Type
+	for _, pkg := range l.packages {
+		pkgInfo := l.prog.Package(pkg.Path)
+		if pkgInfo == nil {
+			continue
+		}
+		for _, t := range pkg.Types {
+			obj := pkgInfo.Pkg.Scope().Lookup(t.Name)
+			if obj == nil {
+				continue
+			}
+			if types.Implements(obj.Type(), ifaceType) || types.Implements(types.NewPointer(obj.Type()), ifaceType) {
+				implementations = append(implementations, t)
+			}
+		}
+	}
+	sort.Slice(implementations, func(i, j int) bool {
+		if implementations[i].Package != implementations[j].Package {
+			return implementations[i].Package < implementations[j].Package
+		}
+		return implementations[i].Name < implementations[j].Name
+	})
+	return implementations, nil
+}
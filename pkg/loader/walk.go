@@ -0,0 +1,75 @@
+package loader
+
+import "go/types"
+
+// WalkedField is a field found by Walk.
+type WalkedField struct {
+	Field
+	// Path is the key path of the field, as formatted by JoinKeyPath. Slice elements are addressed
+	// as `key[*]` and map values as `key.*`.
+	Path string
+	// Owner is the type that declares the field.
+	Owner Type
+	// Parent is the field that holds the values of Owner, or nil for the fields of the root type.
+	Parent *WalkedField
+	// Elem is the loaded type of the values of the field, found through pointers, slices, arrays
+	// and maps, or nil if the values are not of a loaded type.
+	Elem *Type
+	// ElemPath is the key path the values of the field are found at, which is Path unless the
+	// field is a slice, array or map.
+	ElemPath string
+}
+
+// Walk calls fn for each field of rootType and, where fn returns true, for the fields of the Elem
+// type of the field, depth first. Types are not walked again within themselves, so recursive types
+// terminate. The fields of each type are walked in the order returned by fields, or in declaration
+// order if fields is nil.
+func Walk(rootType Type, packages []Package, fields func([]Field) []Field, fn func(*WalkedField) bool) {
+	visited := map[string]bool{}
+	var walk func(pkgType Type, path string, parent *WalkedField)
+	walk = func(pkgType Type, path string, parent *WalkedField) {
+		qualifiedName := pkgType.Package + "." + pkgType.Name
+		if visited[qualifiedName] {
+			return
+		}
+		visited[qualifiedName] = true
+		defer delete(visited, qualifiedName)
+
+		typeFields := pkgType.Fields
+		if fields != nil {
+			typeFields = fields(typeFields)
+		}
+		for _, field := range typeFields {
+			walked := &WalkedField{Field: field, Path: JoinKeyPath(path, field.JSONProperty), Owner: pkgType, Parent: parent}
+			var elemType types.Type
+			elemType, walked.ElemPath = ElementType(field.Type, walked.Path)
+			if elem, found := FindNamedType(packages, elemType); found {
+				walked.Elem = &elem
+			}
+			if fn(walked) && walked.Elem != nil {
+				walk(*walked.Elem, walked.ElemPath, walked)
+			}
+		}
+	}
+	walk(rootType, "", nil)
+}
+
+// ElementType returns the type of the values held by t and the key path they are found at,
+// unwrapping pointers, slices, arrays and maps. Slice and array elements are found at `path[*]`
+// and map values at `path.*`.
+func ElementType(t types.Type, path string) (types.Type, string) {
+	for {
+		switch typ := t.(type) {
+		case *types.Pointer:
+			t = typ.Elem()
+		case *types.Slice:
+			t, path = typ.Elem(), AllItemsKeyPath(path)
+		case *types.Array:
+			t, path = typ.Elem(), AllItemsKeyPath(path)
+		case *types.Map:
+			t, path = typ.Elem(), AllValuesKeyPath(path)
+		default:
+			return t, path
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package loader_test
+
+import (
+	"go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+var _ = Describe("Walk", func() {
+	var (
+		config   Type
+		packages []Package
+	)
+
+	BeforeEach(func() {
+		var err error
+		packages, err = New([]string{"github.com/jimmidyson/prettyconf/pkg/loader/testdata/walk"}, logger).Load()
+		Expect(err).NotTo(HaveOccurred())
+		config, _ = FindType(packages, "github.com/jimmidyson/prettyconf/pkg/loader/testdata/walk", "Config")
+	})
+
+	It("walks nested types through pointers, slices and maps", func() {
+		var paths []string
+		Walk(config, packages, nil, func(field *WalkedField) bool {
+			path := field.Owner.Name + "." + field.Name + " " + field.Path
+			if field.Elem != nil {
+				path += " " + field.Elem.Name + " " + field.ElemPath
+			}
+			paths = append(paths, path)
+			return true
+		})
+		Expect(paths).To(Equal([]string{
+			"Config.Name name",
+			"Config.Servers servers Server servers[*]",
+			"Server.Labels servers[*].labels Label servers[*].labels.*",
+			"Label.Value servers[*].labels.*.value",
+			"Config.Root root Node root",
+			"Node.Children root.children Node root.children[*]",
+		}))
+	})
+
+	It("walks fields in the order returned by fields", func() {
+		var paths []string
+		reversed := func(fields []Field) []Field {
+			sorted := make([]Field, 0, len(fields))
+			for i := len(fields) - 1; i >= 0; i-- {
+				sorted = append(sorted, fields[i])
+			}
+			return sorted
+		}
+		Walk(config, packages, reversed, func(field *WalkedField) bool {
+			paths = append(paths, field.Path)
+			return field.Name != "Servers"
+		})
+		Expect(paths).To(Equal([]string{"root", "root.children", "servers", "name"}))
+	})
+
+	It("links fields to their parents", func() {
+		var value *WalkedField
+		Walk(config, packages, nil, func(field *WalkedField) bool {
+			if field.Name == "Value" {
+				value = field
+			}
+			return true
+		})
+		Expect(value).NotTo(BeNil())
+		Expect(value.Parent.Name).To(Equal("Labels"))
+		Expect(value.Parent.Parent.Name).To(Equal("Servers"))
+		Expect(value.Parent.Parent.Parent).To(BeNil())
+	})
+})
+
+var _ = Describe("ElementType", func() {
+	It("unwraps pointers, slices, arrays and maps", func() {
+		t, path := ElementType(types.NewPointer(types.NewSlice(types.NewMap(types.Typ[types.String], types.NewArray(types.Typ[types.Int], 2)))), "key")
+		Expect(t).To(Equal(types.Typ[types.Int]))
+		Expect(path).To(Equal("key[*].*[*]"))
+	})
+})
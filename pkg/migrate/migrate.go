@@ -0,0 +1,282 @@
+// Package migrate rewrites config documents written for an older version of a config type into the
+// current shape, following the `+renamedFrom` and `+movedFrom` markers on its fields.
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// ChangeKind is the kind of a Change.
+type ChangeKind string
+
+const (
+	// Renamed is used for keys renamed within the same mapping.
+	Renamed ChangeKind = "renamed"
+	// Moved is used for keys moved to a different mapping.
+	Moved ChangeKind = "moved"
+	// Skipped is used for old keys that could not be migrated because the new key is already set.
+	Skipped ChangeKind = "skipped"
+)
+
+// Change is a change made, or skipped, while migrating a document.
+type Change struct {
+	Kind ChangeKind
+	// From is the key path of the old key.
+	From string
+	// To is the key path of the new key.
+	To string
+	// Line is the line of the old key in the original document.
+	Line int
+}
+
+func (c Change) String() string {
+	if c.Kind == Skipped {
+		return fmt.Sprintf("%d: skipped %s: %s is already set", c.Line, c.From, c.To)
+	}
+	return fmt.Sprintf("%d: %s %s to %s", c.Line, c.Kind, c.From, c.To)
+}
+
+// MigrateFor loads the type of conf and migrates the YAML or JSON document data to it. See Migrate.
+func MigrateFor(data []byte, conf interface{}, logger logr.Logger) ([]byte, []Change, error) {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Migrate(data, rootType, packages)
+}
+
+// Migrate rewrites the YAML or JSON document data into the current shape of the loaded type
+// rootType, returning the migrated document and the changes made. YAML documents keep their
+// comments, which move with their keys. JSON documents are written back as indented JSON with
+// sorted keys.
+//
+// Keys are first moved from the paths in `+movedFrom` markers, which are paths in the old
+// document, and then renamed from the keys in `+renamedFrom` markers. An old key is left in place
+// if the new key is already set.
+func Migrate(data []byte, rootType loader.Type, packages []loader.Package) ([]byte, []Change, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse config document")
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return data, nil, nil
+	}
+
+	changes, err := MigrateNode(document.Content[0], rootType, packages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var decoded interface{}
+		if err := document.Content[0].Decode(&decoded); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to decode migrated document")
+		}
+		migrated, err := json.MarshalIndent(decoded, "", "  ")
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to marshal migrated document to json")
+		}
+		return append(migrated, '\n'), changes, nil
+	}
+
+	migrated, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal migrated document")
+	}
+	return migrated, changes, nil
+}
+
+// MigrateNode migrates the mapping node root of a document in place. See Migrate.
+func MigrateNode(root *yaml.Node, rootType loader.Type, packages []loader.Package) ([]Change, error) {
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("expected the document to be a mapping")
+	}
+	m := &migrator{root: root, packages: packages}
+	m.moveFields(rootType, "", map[string]bool{})
+	m.renameFields(root, rootType, "")
+	return m.changes, nil
+}
+
+type migrator struct {
+	root     *yaml.Node
+	packages []loader.Package
+	changes  []Change
+}
+
+// moveFields moves the keys of the fields marked with `+movedFrom` reachable from pkgType at path
+// through nested structs. Fields in sequences and maps are not moved as their old paths would be
+// ambiguous.
+func (m *migrator) moveFields(pkgType loader.Type, path string, visited map[string]bool) {
+	qualifiedName := pkgType.Package + "." + pkgType.Name
+	if visited[qualifiedName] {
+		return
+	}
+	visited[qualifiedName] = true
+	defer delete(visited, qualifiedName)
+
+	for _, field := range pkgType.Fields {
+		fieldPath := loader.JoinKeyPath(path, field.JSONProperty)
+		for _, oldPath := range field.Markers.List(loader.MovedFromMarker) {
+			m.move(oldPath, fieldPath)
+		}
+		if nestedType, found := loader.FindNamedType(m.packages, field.Type); found {
+			m.moveFields(nestedType, fieldPath, visited)
+		}
+	}
+}
+
+func (m *migrator) move(oldPath, newPath string) {
+	oldKeys := strings.Split(oldPath, ".")
+	oldParent := lookup(m.root, oldKeys[:len(oldKeys)-1])
+	if oldParent == nil {
+		return
+	}
+	idx := keyIndex(oldParent, oldKeys[len(oldKeys)-1])
+	if idx < 0 {
+		return
+	}
+	keyNode, valueNode := oldParent.Content[idx], oldParent.Content[idx+1]
+
+	newKeys := strings.Split(newPath, ".")
+	if existing := lookup(m.root, newKeys[:len(newKeys)-1]); existing != nil && keyIndex(existing, newKeys[len(newKeys)-1]) >= 0 {
+		m.changes = append(m.changes, Change{Kind: Skipped, From: oldPath, To: newPath, Line: keyNode.Line})
+		return
+	}
+
+	newParent := m.root
+	for _, key := range newKeys[:len(newKeys)-1] {
+		if newParent = childMapping(newParent, key); newParent == nil {
+			m.changes = append(m.changes, Change{Kind: Skipped, From: oldPath, To: newPath, Line: keyNode.Line})
+			return
+		}
+	}
+	oldParent.Content = append(oldParent.Content[:idx:idx], oldParent.Content[idx+2:]...)
+	if len(oldParent.Content) == 0 && len(oldKeys) > 1 {
+		// Remove the mapping the key was moved out of, as it would otherwise be left empty.
+		grandparent := lookup(m.root, oldKeys[:len(oldKeys)-2])
+		if parentIdx := keyIndex(grandparent, oldKeys[len(oldKeys)-2]); parentIdx >= 0 {
+			grandparent.Content = append(grandparent.Content[:parentIdx:parentIdx], grandparent.Content[parentIdx+2:]...)
+		}
+	}
+	keyNode.Value = newKeys[len(newKeys)-1]
+	newParent.Content = append(newParent.Content, keyNode, valueNode)
+	m.changes = append(m.changes, Change{Kind: Moved, From: oldPath, To: newPath, Line: keyNode.Line})
+}
+
+// renameFields renames the keys of the fields of pkgType marked with `+renamedFrom` in the mapping
+// node, and in all nested mappings, including those in sequences and maps.
+func (m *migrator) renameFields(node *yaml.Node, pkgType loader.Type, path string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for _, field := range pkgType.Fields {
+		fieldPath := loader.JoinKeyPath(path, field.JSONProperty)
+		for _, oldKey := range field.Markers.List(loader.RenamedFromMarker) {
+			idx := keyIndex(node, oldKey)
+			if idx < 0 {
+				continue
+			}
+			oldPath := loader.JoinKeyPath(path, oldKey)
+			keyNode := node.Content[idx]
+			if keyIndex(node, field.JSONProperty) >= 0 {
+				m.changes = append(m.changes, Change{Kind: Skipped, From: oldPath, To: fieldPath, Line: keyNode.Line})
+				continue
+			}
+			keyNode.Value = field.JSONProperty
+			m.changes = append(m.changes, Change{Kind: Renamed, From: oldPath, To: fieldPath, Line: keyNode.Line})
+		}
+
+		idx := keyIndex(node, field.JSONProperty)
+		if idx < 0 {
+			continue
+		}
+		m.renameValue(node.Content[idx+1], field.Type, fieldPath)
+	}
+}
+
+// renameValue renames fields in the value node of type t at path.
+func (m *migrator) renameValue(node *yaml.Node, t types.Type, path string) {
+	if nestedType, found := loader.FindNamedType(m.packages, t); found {
+		m.renameFields(node, nestedType, path)
+		return
+	}
+	switch typ := t.(type) {
+	case *types.Pointer:
+		m.renameValue(node, typ.Elem(), path)
+	case *types.Slice:
+		if node.Kind == yaml.SequenceNode {
+			for i, item := range node.Content {
+				m.renameValue(item, typ.Elem(), loader.IndexKeyPath(path, i))
+			}
+		}
+	case *types.Array:
+		if node.Kind == yaml.SequenceNode {
+			for i, item := range node.Content {
+				m.renameValue(item, typ.Elem(), loader.IndexKeyPath(path, i))
+			}
+		}
+	case *types.Map:
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				m.renameValue(node.Content[i+1], typ.Elem(), loader.JoinKeyPath(path, node.Content[i].Value))
+			}
+		}
+	}
+}
+
+// lookup returns the mapping node at keys from node, or nil if there is none.
+func lookup(node *yaml.Node, keys []string) *yaml.Node {
+	for _, key := range keys {
+		idx := keyIndex(node, key)
+		if idx < 0 {
+			return nil
+		}
+		node = node.Content[idx+1]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	return node
+}
+
+// childMapping returns the mapping node at key in the mapping node, adding it if it is missing or
+// null. It returns nil if key holds any other value.
+func childMapping(node *yaml.Node, key string) *yaml.Node {
+	if idx := keyIndex(node, key); idx >= 0 {
+		child := node.Content[idx+1]
+		switch {
+		case child.Kind == yaml.MappingNode:
+		case child.Kind == yaml.ScalarNode && child.ShortTag() == "!!null":
+			child.Kind, child.Tag, child.Value = yaml.MappingNode, "!!map", ""
+		default:
+			return nil
+		}
+		return child
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+	return child
+}
+
+// keyIndex returns the index of the key node key in the mapping node, or -1 if it is not found.
+func keyIndex(node *yaml.Node, key string) int {
+	if node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,62 @@
+package migrate_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/migrate"
+	"github.com/jimmidyson/prettyconf/pkg/migrate/testdata"
+)
+
+var _ = Describe("Migrate", func() {
+	It("migrates YAML documents keeping comments", func() {
+		old, err := ioutil.ReadFile(filepath.Join("testdata", "old.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "migrated.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		migrated, changes, err := migrate.MigrateFor(old, testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(migrated)).To(Equal(string(expected)))
+		Expect(changes).To(Equal([]migrate.Change{
+			{Kind: migrate.Moved, From: "server.certFile", To: "server.tls.certFile", Line: 5},
+			{Kind: migrate.Moved, From: "logging.level", To: "logLevel", Line: 12},
+			{Kind: migrate.Renamed, From: "server.addr", To: "server.listenAddress", Line: 4},
+			{Kind: migrate.Renamed, From: "backends[0].endpoint", To: "backends[0].url", Line: 7},
+			{Kind: migrate.Skipped, From: "backends[1].endpoint", To: "backends[1].url", Line: 9},
+		}))
+		Expect(changes[4].String()).To(Equal("9: skipped backends[1].endpoint: backends[1].url is already set"))
+	})
+
+	It("migrates JSON documents", func() {
+		migrated, changes, err := migrate.MigrateFor(
+			[]byte(`{"server": {"listen": "localhost"}, "logging": {"level": "debug"}}`), testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(migrated)).To(MatchJSON(`{"server": {"listenAddress": "localhost"}, "logLevel": "debug"}`))
+		Expect(changes).To(HaveLen(2))
+	})
+
+	It("migrates keys renamed more than once", func() {
+		migrated, changes, err := migrate.MigrateFor(
+			[]byte("backends:\n  - address: a\n  - endpoint: b\n"), testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(migrated)).To(Equal("backends:\n  - url: a\n  - url: b\n"))
+		Expect(changes).To(Equal([]migrate.Change{
+			{Kind: migrate.Renamed, From: "backends[0].address", To: "backends[0].url", Line: 2},
+			{Kind: migrate.Renamed, From: "backends[1].endpoint", To: "backends[1].url", Line: 3},
+		}))
+	})
+
+	It("does not overwrite values that are not mappings", func() {
+		migrated, changes, err := migrate.MigrateFor(
+			[]byte("server:\n  tls: true\n  certFile: cert.pem\n"), testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(migrated)).To(Equal("server:\n    tls: true\n    certFile: cert.pem\n"))
+		Expect(changes).To(Equal([]migrate.Change{
+			{Kind: migrate.Skipped, From: "server.certFile", To: "server.tls.certFile", Line: 3},
+		}))
+	})
+})
@@ -0,0 +1,36 @@
+package testdata
+
+// Config is the current shape of a config.
+type Config struct {
+	// Server configures the server.
+	Server ServerConfig `json:"server"`
+	// Backends configure backends.
+	Backends []Backend `json:"backends,omitempty"`
+	// LogLevel is the log level.
+	// +movedFrom=logging.level
+	LogLevel string `json:"logLevel,omitempty"`
+}
+
+// ServerConfig configures the server.
+type ServerConfig struct {
+	// ListenAddress is the address to listen on.
+	// +renamedFrom=addr,listen
+	ListenAddress string `json:"listenAddress"`
+	// TLS configures TLS.
+	TLS *TLSConfig `json:"tls,omitempty"`
+}
+
+// TLSConfig configures TLS.
+type TLSConfig struct {
+	// CertFile is the path to the certificate.
+	// +movedFrom=server.certFile
+	CertFile string `json:"certFile"`
+}
+
+// Backend configures a backend.
+type Backend struct {
+	// URL is the backend URL.
+	// +renamedFrom=address
+	// +renamedFrom=endpoint
+	URL string `json:"url"`
+}
@@ -0,0 +1,291 @@
+// Package model is a serializable form of the loaded config metadata that does not depend on
+// go/types, so that it can be cached, compared across runs and consumed by other tools and
+// languages.
+//
+// A catalog is written as JSON by `prettyconf export`, in the shape of Catalog:
+//
+//	{
+//	  "version": 1,
+//	  "packages": [{
+//	    "path": "example.com/app/config",
+//	    "types": [{
+//	      "name": "Config",
+//	      "position": {"file": "config.go", "line": 9, "column": 6},
+//	      "fields": [{
+//	        "name": "Timeout",
+//	        "jsonProperty": "timeout",
+//	        "type": {"kind": "named", "package": "time", "name": "Duration", "underlying": {"kind": "basic", "name": "int64"}},
+//	        "typeName": "time.Duration",
+//	        "doc": "Timeout is how long to wait.",
+//	        "required": true
+//	      }]
+//	    }]
+//	  }]
+//	}
+//
+// Properties with zero values are omitted. Read reads a catalog back and Catalog.LoaderPackages
+// converts it to loaded packages for printing without the package source.
+package model
+
+import (
+	"encoding/json"
+	"go/types"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// Version is the version of the model written by Write. It is incremented for changes that are
+// not backwards compatible.
+const Version = 1
+
+// Catalog holds the loaded packages.
+type Catalog struct {
+	Version  int       `json:"version"`
+	Packages []Package `json:"packages"`
+}
+
+// Package is a loaded package.
+type Package struct {
+	Path  string `json:"path"`
+	Doc   string `json:"doc,omitempty"`
+	Types []Type `json:"types"`
+}
+
+// Type is a loaded struct type.
+type Type struct {
+	Name       string              `json:"name"`
+	Doc        string              `json:"doc,omitempty"`
+	Markers    map[string][]string `json:"markers,omitempty"`
+	Union      *Union              `json:"union,omitempty"`
+	Deprecated *Deprecation        `json:"deprecated,omitempty"`
+	Position   *Position           `json:"position,omitempty"`
+	Fields     []Field             `json:"fields"`
+}
+
+// Field is a field of a struct type.
+type Field struct {
+	// Name is the Go name of the field.
+	Name string `json:"name"`
+	// JSONProperty is the key of the field in serialized configs.
+	JSONProperty string  `json:"jsonProperty"`
+	Type         TypeRef `json:"type"`
+	// TypeName is the Go type of the field as a string, e.g. `[]*example.com/pkg.Server`.
+	TypeName   string              `json:"typeName"`
+	Doc        string              `json:"doc,omitempty"`
+	Required   bool                `json:"required,omitempty"`
+	Anonymous  bool                `json:"anonymous,omitempty"`
+	Sensitive  bool                `json:"sensitive,omitempty"`
+	Hidden     bool                `json:"hidden,omitempty"`
+	Order      int                 `json:"order,omitempty"`
+	Section    string              `json:"section,omitempty"`
+	Example    string              `json:"example,omitempty"`
+	Summary    string              `json:"summary,omitempty"`
+	Env        string              `json:"env,omitempty"`
+	Enum       []string            `json:"enum,omitempty"`
+	Deprecated *Deprecation        `json:"deprecated,omitempty"`
+	Markers    map[string][]string `json:"markers,omitempty"`
+	Position   *Position           `json:"position,omitempty"`
+}
+
+// Kind is the kind of a TypeRef.
+type Kind string
+
+const (
+	// Basic is used for predeclared types such as `string` and `int64`.
+	Basic Kind = "basic"
+	// Named is used for declared types, such as `time.Duration` or config structs.
+	Named     Kind = "named"
+	Pointer   Kind = "pointer"
+	Slice     Kind = "slice"
+	Array     Kind = "array"
+	Map       Kind = "map"
+	Struct    Kind = "struct"
+	Interface Kind = "interface"
+	// Other is used for types that cannot be serialized, such as channels and functions.
+	Other Kind = "other"
+)
+
+// TypeRef describes the type of a field.
+type TypeRef struct {
+	Kind Kind `json:"kind"`
+	// Name is the name of basic and named types, or the Go type of other types.
+	Name string `json:"name,omitempty"`
+	// Package is the import path of named types.
+	Package string `json:"package,omitempty"`
+	// Underlying is the underlying type of named types. The fields of named struct types are
+	// described by the Type of the same name in the catalog, if it was loaded. It is omitted where
+	// a recursive named type recurs within its own underlying type.
+	Underlying *TypeRef `json:"underlying,omitempty"`
+	// Elem is the element type of pointers, slices, arrays and maps.
+	Elem *TypeRef `json:"elem,omitempty"`
+	// Key is the key type of maps.
+	Key *TypeRef `json:"key,omitempty"`
+	// Len is the length of arrays.
+	Len int64 `json:"len,omitempty"`
+}
+
+// Union describes a struct of which exactly one member field may be set.
+type Union struct {
+	Discriminator string        `json:"discriminator,omitempty"`
+	Members       []UnionMember `json:"members"`
+}
+
+// UnionMember is a member field of a union.
+type UnionMember struct {
+	JSONProperty string `json:"jsonProperty"`
+	Value        string `json:"value,omitempty"`
+}
+
+// Position is the position of a type or field declaration in the source of its package.
+type Position struct {
+	// File is the name of the source file in the directory of the package, so that positions do
+	// not depend on where the package was loaded from.
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+}
+
+// Deprecation describes a deprecated type or field.
+type Deprecation struct {
+	Message     string `json:"message,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// New returns the catalog of the loaded packages.
+func New(packages []loader.Package) Catalog {
+	catalog := Catalog{Version: Version, Packages: make([]Package, 0, len(packages))}
+	for _, pkg := range packages {
+		p := Package{Path: pkg.Path, Doc: pkg.Doc, Types: make([]Type, 0, len(pkg.Types))}
+		for _, t := range pkg.Types {
+			p.Types = append(p.Types, newType(t))
+		}
+		catalog.Packages = append(catalog.Packages, p)
+	}
+	return catalog
+}
+
+func newType(t loader.Type) Type {
+	typ := Type{
+		Name:       t.Name,
+		Doc:        t.Doc,
+		Markers:    t.Markers,
+		Deprecated: newDeprecation(t.Deprecated),
+		Position:   newPosition(t.Position),
+		Fields:     make([]Field, 0, len(t.Fields)),
+	}
+	if t.Union != nil {
+		typ.Union = &Union{Discriminator: t.Union.Discriminator, Members: make([]UnionMember, 0, len(t.Union.Members))}
+		for _, m := range t.Union.Members {
+			typ.Union.Members = append(typ.Union.Members, UnionMember{JSONProperty: m.JSONProperty, Value: m.Value})
+		}
+	}
+	for _, f := range t.Fields {
+		typ.Fields = append(typ.Fields, Field{
+			Name:         f.Name,
+			JSONProperty: f.JSONProperty,
+			Type:         NewTypeRef(f.Type),
+			TypeName:     f.TypeName,
+			Doc:          f.Doc,
+			Required:     f.JSONRequired,
+			Anonymous:    f.Anonymous,
+			Sensitive:    f.Sensitive,
+			Hidden:       f.Hidden,
+			Order:        f.Order,
+			Section:      f.Section,
+			Example:      f.Example,
+			Summary:      f.Summary,
+			Env:          f.Env,
+			Enum:         f.Enum,
+			Deprecated:   newDeprecation(f.Deprecated),
+			Markers:      f.Markers,
+			Position:     newPosition(f.Position),
+		})
+	}
+	return typ
+}
+
+func newDeprecation(d *loader.Deprecation) *Deprecation {
+	if d == nil {
+		return nil
+	}
+	return &Deprecation{Message: d.Message, Replacement: d.Replacement}
+}
+
+func newPosition(p loader.Position) *Position {
+	if !p.IsValid() {
+		return nil
+	}
+	return &Position{File: filepath.Base(p.Filename), Line: p.Line, Column: p.Column}
+}
+
+// NewTypeRef returns the TypeRef describing t.
+func NewTypeRef(t types.Type) TypeRef {
+	return newTypeRef(t, map[*types.Named]bool{})
+}
+
+// newTypeRef describes t. Named types in visiting are being described by a caller, so only their
+// names are described.
+func newTypeRef(t types.Type, visiting map[*types.Named]bool) TypeRef {
+	switch typ := t.(type) {
+	case *types.Basic:
+		return TypeRef{Kind: Basic, Name: typ.Name()}
+	case *types.Named:
+		ref := TypeRef{Kind: Named, Name: typ.Obj().Name()}
+		if pkg := typ.Obj().Pkg(); pkg != nil {
+			ref.Package = pkg.Path()
+			if idx := strings.Index(ref.Package, "vendor/"); idx > -1 {
+				ref.Package = ref.Package[idx+len("vendor/"):]
+			}
+		}
+		if visiting[typ] {
+			return ref
+		}
+		visiting[typ] = true
+		defer delete(visiting, typ)
+		ref.Underlying = newTypeRefPtr(typ.Underlying(), visiting)
+		return ref
+	case *types.Pointer:
+		return TypeRef{Kind: Pointer, Elem: newTypeRefPtr(typ.Elem(), visiting)}
+	case *types.Slice:
+		return TypeRef{Kind: Slice, Elem: newTypeRefPtr(typ.Elem(), visiting)}
+	case *types.Array:
+		return TypeRef{Kind: Array, Elem: newTypeRefPtr(typ.Elem(), visiting), Len: typ.Len()}
+	case *types.Map:
+		return TypeRef{Kind: Map, Key: newTypeRefPtr(typ.Key(), visiting), Elem: newTypeRefPtr(typ.Elem(), visiting)}
+	case *types.Struct:
+		return TypeRef{Kind: Struct}
+	case *types.Interface:
+		return TypeRef{Kind: Interface}
+	default:
+		return TypeRef{Kind: Other, Name: t.String()}
+	}
+}
+
+func newTypeRefPtr(t types.Type, visiting map[*types.Named]bool) *TypeRef {
+	ref := newTypeRef(t, visiting)
+	return &ref
+}
+
+// Write writes the catalog to w as indented JSON.
+func (c Catalog) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(c), "failed to write catalog")
+}
+
+// Read reads a catalog written by Write, returning an error if it has a different version.
+func Read(r io.Reader) (Catalog, error) {
+	var c Catalog
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return Catalog{}, errors.Wrap(err, "failed to read catalog")
+	}
+	if c.Version != Version {
+		return Catalog{}, errors.Errorf("unsupported catalog version %d, expected %d", c.Version, Version)
+	}
+	return c, nil
+}
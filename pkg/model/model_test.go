@@ -0,0 +1,78 @@
+package model_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/model"
+)
+
+var _ = Describe("Model", func() {
+	var catalog model.Catalog
+
+	BeforeEach(func() {
+		packages, err := loader.New([]string{"github.com/jimmidyson/prettyconf/pkg/model/testdata"}, logger).Load()
+		Expect(err).NotTo(HaveOccurred())
+		catalog = model.New(packages)
+	})
+
+	It("describes field types without go/types", func() {
+		fields := catalog.Packages[0].Types[0].Fields
+		Expect(fields[0].Type).To(Equal(model.TypeRef{
+			Kind: model.Named, Package: "time", Name: "Duration",
+			Underlying: &model.TypeRef{Kind: model.Basic, Name: "int64"},
+		}))
+		Expect(fields[2].Type).To(Equal(model.TypeRef{
+			Kind: model.Slice,
+			Elem: &model.TypeRef{Kind: model.Pointer, Elem: &model.TypeRef{
+				Kind: model.Named, Package: "github.com/jimmidyson/prettyconf/pkg/model/testdata", Name: "Server",
+				Underlying: &model.TypeRef{Kind: model.Struct},
+			}},
+		}))
+	})
+
+	It("describes recursive types by name where they recur", func() {
+		fields := catalog.Packages[0].Types[0].Fields
+		Expect(fields[7].Type).To(Equal(model.TypeRef{
+			Kind: model.Named, Package: "github.com/jimmidyson/prettyconf/pkg/model/testdata", Name: "Values",
+			Underlying: &model.TypeRef{
+				Kind: model.Map,
+				Key:  &model.TypeRef{Kind: model.Basic, Name: "string"},
+				Elem: &model.TypeRef{Kind: model.Named, Package: "github.com/jimmidyson/prettyconf/pkg/model/testdata", Name: "Values"},
+			},
+		}))
+	})
+
+	It("round-trips through JSON", func() {
+		var buf bytes.Buffer
+		Expect(catalog.Write(&buf)).To(Succeed())
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "catalog.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal(string(expected)))
+
+		read, err := model.Read(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read).To(Equal(catalog))
+	})
+
+	It("converts back to loaded packages", func() {
+		packages, err := catalog.LoaderPackages()
+		Expect(err).NotTo(HaveOccurred())
+		for _, t := range packages[0].Types {
+			for _, f := range t.Fields {
+				Expect(f.Type.String()).To(Equal(f.TypeName))
+			}
+		}
+		Expect(model.New(packages)).To(Equal(catalog))
+	})
+
+	It("rejects unknown versions", func() {
+		_, err := model.Read(bytes.NewBufferString(`{"version": 2}`))
+		Expect(err).To(MatchError("unsupported catalog version 2, expected 1"))
+	})
+})
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"go/token"
+	"go/types"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// LoaderPackages returns the loaded packages described by the catalog, so that a catalog read from
+// a file can be used in place of loading package source, e.g. with printer.WithPackages.
+//
+// Field types are rebuilt from their TypeRefs. Named struct types that are in the catalog have
+// their fields, without struct tags; other named struct types and all interfaces are empty.
+// Positions only hold the name of the source file.
+func (c Catalog) LoaderPackages() ([]loader.Package, error) {
+	b := &typeBuilder{
+		catalog:  c,
+		packages: map[string]*types.Package{},
+		named:    map[string]*types.Named{},
+	}
+	packages := make([]loader.Package, 0, len(c.Packages))
+	for _, pkg := range c.Packages {
+		p := loader.Package{Path: pkg.Path, Doc: pkg.Doc, Types: make([]loader.Type, 0, len(pkg.Types))}
+		for _, t := range pkg.Types {
+			typ, err := b.loaderType(pkg.Path, t)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid type %s.%s", pkg.Path, t.Name)
+			}
+			p.Types = append(p.Types, typ)
+		}
+		packages = append(packages, p)
+	}
+	return packages, nil
+}
+
+type typeBuilder struct {
+	catalog  Catalog
+	packages map[string]*types.Package
+	named    map[string]*types.Named
+}
+
+func (b *typeBuilder) loaderType(pkgPath string, t Type) (loader.Type, error) {
+	typ := loader.Type{
+		Name:       t.Name,
+		Package:    pkgPath,
+		Doc:        t.Doc,
+		Markers:    t.Markers,
+		Deprecated: loaderDeprecation(t.Deprecated),
+		Position:   loaderPosition(t.Position),
+		Fields:     make([]loader.Field, 0, len(t.Fields)),
+	}
+	if t.Union != nil {
+		typ.Union = &loader.Union{Discriminator: t.Union.Discriminator, Members: make([]loader.UnionMember, 0, len(t.Union.Members))}
+		for _, m := range t.Union.Members {
+			typ.Union.Members = append(typ.Union.Members, loader.UnionMember{JSONProperty: m.JSONProperty, Value: m.Value})
+		}
+	}
+	for _, f := range t.Fields {
+		fieldType, err := b.typ(f.Type)
+		if err != nil {
+			return loader.Type{}, errors.Wrapf(err, "invalid type of field %s", f.Name)
+		}
+		typ.Fields = append(typ.Fields, loader.Field{
+			Name:         f.Name,
+			Doc:          f.Doc,
+			Anonymous:    f.Anonymous,
+			JSONRequired: f.Required,
+			JSONProperty: f.JSONProperty,
+			Type:         fieldType,
+			TypeName:     f.TypeName,
+			Sensitive:    f.Sensitive,
+			Hidden:       f.Hidden,
+			Order:        f.Order,
+			Section:      f.Section,
+			Example:      f.Example,
+			Summary:      f.Summary,
+			Env:          f.Env,
+			Enum:         f.Enum,
+			Deprecated:   loaderDeprecation(f.Deprecated),
+			Markers:      f.Markers,
+			Position:     loaderPosition(f.Position),
+		})
+	}
+	return typ, nil
+}
+
+// typ returns the go/types type described by ref.
+func (b *typeBuilder) typ(ref TypeRef) (types.Type, error) {
+	switch ref.Kind {
+	case Basic:
+		obj := types.Universe.Lookup(ref.Name)
+		if obj == nil {
+			return nil, errors.Errorf("unknown basic type %q", ref.Name)
+		}
+		if _, ok := obj.(*types.TypeName); !ok {
+			return nil, errors.Errorf("unknown basic type %q", ref.Name)
+		}
+		return obj.Type(), nil
+	case Named:
+		return b.namedType(ref)
+	case Pointer, Slice, Array, Map:
+		if ref.Elem == nil {
+			return nil, errors.Errorf("%s type has no element type", ref.Kind)
+		}
+		elem, err := b.typ(*ref.Elem)
+		if err != nil {
+			return nil, err
+		}
+		switch ref.Kind {
+		case Pointer:
+			return types.NewPointer(elem), nil
+		case Slice:
+			return types.NewSlice(elem), nil
+		case Array:
+			return types.NewArray(elem, ref.Len), nil
+		}
+		if ref.Key == nil {
+			return nil, errors.New("map type has no key type")
+		}
+		key, err := b.typ(*ref.Key)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMap(key, elem), nil
+	case Struct:
+		return types.NewStruct(nil, nil), nil
+	case Interface:
+		return types.NewInterfaceType(nil, nil).Complete(), nil
+	default:
+		return nil, errors.Errorf("unsupported type kind %q", ref.Kind)
+	}
+}
+
+func (b *typeBuilder) namedType(ref TypeRef) (types.Type, error) {
+	qualifiedName := ref.Package + "." + ref.Name
+	if named, ok := b.named[qualifiedName]; ok {
+		return named, nil
+	}
+	if ref.Underlying == nil {
+		return nil, errors.Errorf("named type %s has no underlying type", qualifiedName)
+	}
+
+	var pkg *types.Package
+	if ref.Package != "" {
+		var ok bool
+		if pkg, ok = b.packages[ref.Package]; !ok {
+			pkg = types.NewPackage(ref.Package, path.Base(ref.Package))
+			b.packages[ref.Package] = pkg
+		}
+	}
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, ref.Name, nil), nil, nil)
+	// Register before building the underlying type so recursive types terminate.
+	b.named[qualifiedName] = named
+
+	if t, found := b.findType(ref.Package, ref.Name); found && ref.Underlying.Kind == Struct {
+		fields := make([]*types.Var, 0, len(t.Fields))
+		for _, f := range t.Fields {
+			fieldType, err := b.typ(f.Type)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid type of field %s.%s", qualifiedName, f.Name)
+			}
+			fields = append(fields, types.NewField(token.NoPos, pkg, f.Name, fieldType, f.Anonymous))
+		}
+		named.SetUnderlying(types.NewStruct(fields, nil))
+		return named, nil
+	}
+
+	underlying, err := b.typ(*ref.Underlying)
+	if err != nil {
+		return nil, err
+	}
+	named.SetUnderlying(underlying.Underlying())
+	return named, nil
+}
+
+func (b *typeBuilder) findType(pkgPath, name string) (Type, bool) {
+	for _, pkg := range b.catalog.Packages {
+		if pkg.Path != pkgPath {
+			continue
+		}
+		for _, t := range pkg.Types {
+			if t.Name == name {
+				return t, true
+			}
+		}
+	}
+	return Type{}, false
+}
+
+func loaderDeprecation(d *Deprecation) *loader.Deprecation {
+	if d == nil {
+		return nil
+	}
+	return &loader.Deprecation{Message: d.Message, Replacement: d.Replacement}
+}
+
+func loaderPosition(p *Position) loader.Position {
+	if p == nil {
+		return loader.Position{}
+	}
+	return loader.Position{Filename: p.File, Line: p.Line, Column: p.Column}
+}
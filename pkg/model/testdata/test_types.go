@@ -0,0 +1,56 @@
+package testdata
+
+import "time"
+
+// Mode is a run mode.
+type Mode string
+
+// Config is a config.
+type Config struct {
+	// Timeout is how long to wait.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Mode is the mode to run in.
+	// +enum=fast,slow
+	Mode Mode `json:"mode"`
+	// Servers are the servers.
+	Servers []*Server `json:"servers,omitempty"`
+	// Labels are labels.
+	Labels map[string]string `json:"labels,omitempty" prettyconf:"section=Metadata"`
+	// Digest is a digest.
+	Digest [4]byte `json:"digest,omitempty"`
+	// Extra holds anything.
+	Extra interface{} `json:"extra,omitempty"`
+	// Storage is a union.
+	Storage Storage `json:"storage"`
+	// Values are nested values.
+	Values Values `json:"values,omitempty"`
+}
+
+// Values are nested values.
+type Values map[string]Values
+
+// Server is a server.
+type Server struct {
+	// Address is the address.
+	//
+	// Deprecated: Use URL instead.
+	Address string `json:"address,omitempty"`
+	// URL is the URL.
+	URL string `json:"url"`
+}
+
+// Storage configures storage.
+// +union:discriminator=type
+type Storage struct {
+	// Type selects the storage.
+	Type string `json:"type"`
+	// S3 configures S3.
+	// +union:member=s3
+	S3 *S3 `json:"s3,omitempty"`
+}
+
+// S3 configures S3.
+type S3 struct {
+	// Bucket is the bucket.
+	Bucket string `json:"bucket"`
+}
@@ -0,0 +1,106 @@
+package printer
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// FieldDoc returns the doc of field, starting with its JSON property rather than its Go name.
+func FieldDoc(field loader.Field) string {
+	if strings.HasPrefix(field.Doc, field.Name+" ") {
+		return field.JSONProperty + field.Doc[len(field.Name):]
+	}
+	return field.Doc
+}
+
+// TypeAnnotation returns a compact description of the type of the field, whether it is required
+// and its allowed values, e.g. `Type: int32 | Required | Enum: a,b`.
+func TypeAnnotation(field loader.Field) string {
+	parts := []string{"Type: " + FriendlyTypeName(field.Type)}
+	if field.JSONRequired {
+		parts = append(parts, "Required")
+	} else {
+		parts = append(parts, "Optional")
+	}
+	if len(field.Enum) > 0 {
+		parts = append(parts, "Enum: "+strings.Join(field.Enum, ","))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// UnionAnnotation describes the role of the field in the union, e.g. `One of: s3, gcs.` for the
+// discriminator or `Only set when type is s3.` for a member. It returns an empty string for fields
+// that are not part of the union.
+func UnionAnnotation(union *loader.Union, field loader.Field) string {
+	if union.Discriminator != "" && field.JSONProperty == union.Discriminator {
+		return "One of: " + strings.Join(union.Values(), ", ") + "."
+	}
+	member, isMember := union.Member(field.JSONProperty)
+	if !isMember {
+		return ""
+	}
+	if union.Discriminator != "" {
+		return "Only set when " + union.Discriminator + " is " + member.Value + "."
+	}
+	properties := make([]string, 0, len(union.Members))
+	for _, m := range union.Members {
+		properties = append(properties, m.JSONProperty)
+	}
+	return "Only one of " + strings.Join(properties, ", ") + " may be set."
+}
+
+// FriendlyTypeName describes a type in terms of its serialized form rather than its Go
+// declaration, e.g. `duration`, `list of string` or `map of string to int`. Recursive named types
+// are described by their name where they recur.
+func FriendlyTypeName(t types.Type) string {
+	return friendlyTypeName(t, false, map[*types.Named]bool{})
+}
+
+// StructuralTypeName describes a type as FriendlyTypeName does, but describes named structs as
+// `object` rather than by their name, so that renaming a Go type does not change the description.
+func StructuralTypeName(t types.Type) string {
+	return friendlyTypeName(t, true, map[*types.Named]bool{})
+}
+
+func friendlyTypeName(t types.Type, structural bool, visiting map[*types.Named]bool) string {
+	switch typ := t.(type) {
+	case *types.Basic:
+		return typ.Name()
+	case *types.Pointer:
+		return friendlyTypeName(typ.Elem(), structural, visiting)
+	case *types.Slice:
+		if basic, ok := typ.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return "bytes"
+		}
+		return "list of " + friendlyTypeName(typ.Elem(), structural, visiting)
+	case *types.Array:
+		return "list of " + friendlyTypeName(typ.Elem(), structural, visiting)
+	case *types.Map:
+		return "map of " + friendlyTypeName(typ.Key(), structural, visiting) + " to " + friendlyTypeName(typ.Elem(), structural, visiting)
+	case *types.Interface:
+		return "any"
+	case *types.Struct:
+		return "object"
+	case *types.Named:
+		if pkg := typ.Obj().Pkg(); pkg != nil && pkg.Path() == "time" {
+			switch typ.Obj().Name() {
+			case "Duration":
+				return "duration"
+			case "Time":
+				return "timestamp"
+			}
+		}
+		if _, ok := typ.Underlying().(*types.Struct); ok && structural {
+			return "object"
+		} else if ok || visiting[typ] {
+			return typ.Obj().Name()
+		}
+		visiting[typ] = true
+		defer delete(visiting, typ)
+		return friendlyTypeName(typ.Underlying(), structural, visiting)
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,68 @@
+package printer_test
+
+import (
+	"go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+var (
+	timePkg      = types.NewPackage("time", "time")
+	durationType = types.NewNamed(types.NewTypeName(0, timePkg, "Duration", nil), types.Typ[types.Int64], nil)
+	structType   = types.NewNamed(types.NewTypeName(0, types.NewPackage("example.com/pkg", "pkg"), "Server", nil), types.NewStruct(nil, nil), nil)
+	modeType     = types.NewNamed(types.NewTypeName(0, types.NewPackage("example.com/pkg", "pkg"), "Mode", nil), types.Typ[types.String], nil)
+	valuesType   = recursiveMapType()
+)
+
+// recursiveMapType returns `type Values map[string]Values`.
+func recursiveMapType() *types.Named {
+	named := types.NewNamed(types.NewTypeName(0, types.NewPackage("example.com/pkg", "pkg"), "Values", nil), nil, nil)
+	named.SetUnderlying(types.NewMap(types.Typ[types.String], named))
+	return named
+}
+
+var _ = Describe("FriendlyTypeName", func() {
+	DescribeTable("type names",
+		func(t types.Type, expected string) {
+			Expect(printer.FriendlyTypeName(t)).To(Equal(expected))
+		},
+		Entry("basic", types.Typ[types.Int32], "int32"),
+		Entry("pointer", types.NewPointer(types.Typ[types.String]), "string"),
+		Entry("duration", durationType, "duration"),
+		Entry("named basic", modeType, "string"),
+		Entry("named struct", types.NewPointer(structType), "Server"),
+		Entry("bytes", types.NewSlice(types.Typ[types.Byte]), "bytes"),
+		Entry("list", types.NewSlice(structType), "list of Server"),
+		Entry("map", types.NewMap(types.Typ[types.String], types.NewSlice(durationType)), "map of string to list of duration"),
+		Entry("interface", types.NewInterfaceType(nil, nil), "any"),
+		Entry("recursive", valuesType, "map of string to Values"),
+	)
+})
+
+var _ = Describe("StructuralTypeName", func() {
+	DescribeTable("type names",
+		func(t types.Type, expected string) {
+			Expect(printer.StructuralTypeName(t)).To(Equal(expected))
+		},
+		Entry("named struct", types.NewPointer(structType), "object"),
+		Entry("list", types.NewSlice(structType), "list of object"),
+		Entry("named basic", modeType, "string"),
+		Entry("recursive", valuesType, "map of string to Values"),
+	)
+})
+
+var _ = Describe("TypeAnnotation", func() {
+	It("describes required fields with enums", func() {
+		Expect(printer.TypeAnnotation(loader.Field{Type: modeType, JSONRequired: true, Enum: []string{"a", "b"}})).
+			To(Equal("Type: string | Required | Enum: a,b"))
+	})
+
+	It("describes optional fields", func() {
+		Expect(printer.TypeAnnotation(loader.Field{Type: durationType})).To(Equal("Type: duration | Optional"))
+	})
+})
@@ -0,0 +1,89 @@
+package printer
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// visitDynamicValue comments a node marshalled from an interface value, using the dynamic type of
+// the value to find its docs.
+func (v *visitor) visitDynamicValue(node *yaml.Node, value reflect.Value) error {
+	value = indirect(value)
+	if !value.IsValid() || value.Kind() != reflect.Struct || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	pkgType, err := v.dynamicType(value.Type())
+	if err != nil {
+		return err
+	}
+	return v.visitContentNodes(node, pkgType, value)
+}
+
+// dynamicType returns the loaded type for a dynamic type, loading its package on demand if it was
+// not loaded with the config type.
+func (v *visitor) dynamicType(t reflect.Type) (loader.Type, error) {
+	pkgPath, name := t.PkgPath(), t.Name()
+	if pkgType, found := loader.FindType(v.packages, pkgPath, name); found {
+		return pkgType, nil
+	}
+	if pkgPath == "" {
+		return loader.Type{}, errors.Errorf("dynamic type %s is not a named type", t)
+	}
+	if !v.loadedPackagePaths[pkgPath] {
+		if v.loadedPackagePaths == nil {
+			v.loadedPackagePaths = map[string]bool{}
+		}
+		v.loadedPackagePaths[pkgPath] = true
+
+		v.logger.V(5).Info("loading package for dynamic type", "package", pkgPath, "type", name)
+		packages, err := loader.New([]string{pkgPath}, v.logger).Load()
+		if err != nil {
+			if packages, err = reflectPackages(v.logger, err, t); err != nil {
+				return loader.Type{}, err
+			}
+		}
+		v.packages = mergePackages(v.packages, packages...)
+		if pkgType, found := loader.FindType(v.packages, pkgPath, name); found {
+			return pkgType, nil
+		}
+	}
+	return loader.Type{}, errors.Errorf("type %s.%s could not be found", pkgPath, name)
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value for nil.
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func fieldByName(value reflect.Value, name string) reflect.Value {
+	if !value.IsValid() || value.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return value.FieldByName(name)
+}
+
+func index(value reflect.Value, i int) reflect.Value {
+	value = indirect(value)
+	if !value.IsValid() || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || i >= value.Len() {
+		return reflect.Value{}
+	}
+	return value.Index(i)
+}
+
+func mapIndex(value reflect.Value, key string) reflect.Value {
+	value = indirect(value)
+	if !value.IsValid() || value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}
+	}
+	return value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key()))
+}
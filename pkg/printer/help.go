@@ -0,0 +1,205 @@
+package printer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// ANSI escape sequences used to style help output.
+const (
+	styleReset    = "\x1b[0m"
+	styleKey      = "\x1b[1;36m"
+	styleDoc      = "\x1b[2m"
+	styleRequired = "\x1b[1;33m"
+	styleEnum     = "\x1b[32m"
+)
+
+// PrintHelp writes a reference for the type of conf to w, suitable for a `--help-config` command
+// line flag. Every key reachable from the root type is listed by its key path with its type,
+// whether it is required, its allowed values and its doc. Only the type of conf is used, so conf
+// may be a nil pointer.
+//
+// Output is colorized when w is a terminal and the NO_COLOR environment variable is not set, and
+// wrapped to the width of the terminal. Both can be overridden with WithColor and WithTextWidth.
+// With WithPager, output to a terminal is shown through the pager in the PAGER environment
+// variable, or `less`.
+func PrintHelp(conf interface{}, w io.Writer, logger logr.Logger, opts ...Option) error {
+	o := newOptions(opts)
+	confType := derefType(reflect.TypeOf(conf))
+	_, packages, err := loadPackages([]reflect.Type{confType}, logger, o)
+	if err != nil {
+		return err
+	}
+	pkg, found := filterPackage(confType.PkgPath(), packages)
+	if !found {
+		return errors.Errorf("package %s could not be found", confType.PkgPath())
+	}
+	pkgType, found := filterType(confType.Name(), pkg)
+	if !found {
+		return errors.Errorf("type %s.%s could not be found", confType.PkgPath(), confType.Name())
+	}
+
+	terminal := isTerminal(w)
+	color := terminal && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	if o.color != nil {
+		color = *o.color
+	}
+	width := o.textWidth
+	if width == 0 && terminal {
+		width = terminalWidth(w.(*os.File))
+	}
+
+	h := &helpWriter{
+		packages: packages,
+		renderer: &doccomment.Renderer{KeyPaths: KeyPaths(pkgType, packages), TextWidth: width},
+		color:    color,
+		options:  o,
+	}
+	if pkgType.Doc != "" {
+		h.buf.WriteString(h.renderer.In(pkgType.Package).Text(pkgType.Doc))
+		h.buf.WriteString("\n")
+	}
+	h.writeFields(pkgType, "", map[string]bool{})
+
+	if o.pager && terminal {
+		return page(h.buf.Bytes(), w)
+	}
+	_, err = w.Write(h.buf.Bytes())
+	return err
+}
+
+type helpWriter struct {
+	buf      bytes.Buffer
+	packages []loader.Package
+	renderer *doccomment.Renderer
+	color    bool
+	options
+}
+
+func (h *helpWriter) writeFields(pkgType loader.Type, path string, visited map[string]bool) {
+	qualifiedName := pkgType.Package + "." + pkgType.Name
+	if visited[qualifiedName] {
+		return
+	}
+	visited[qualifiedName] = true
+	defer delete(visited, qualifiedName)
+
+	for _, field := range loader.SortFields(pkgType.Fields, h.fieldOrder) {
+		if field.Hidden || (h.hideDeprecated && field.Deprecated != nil) {
+			continue
+		}
+		fieldPath := loader.JoinKeyPath(path, field.JSONProperty)
+		h.writeField(pkgType, field, fieldPath)
+
+		elemType, elemPath := loader.ElementType(field.Type, fieldPath)
+		if fieldType, found := loader.FindNamedType(h.packages, elemType); found {
+			h.writeFields(fieldType, elemPath, visited)
+		}
+	}
+}
+
+func (h *helpWriter) writeField(pkgType loader.Type, field loader.Field, path string) {
+	h.buf.WriteString("\n")
+	h.buf.WriteString(h.style(styleKey, path))
+	h.buf.WriteString(" <" + FriendlyTypeName(field.Type) + ">")
+	if field.JSONRequired {
+		h.buf.WriteString(" " + h.style(styleRequired, "(required)"))
+	}
+	if field.Sensitive {
+		h.buf.WriteString(" (sensitive)")
+	}
+	if field.Deprecated != nil {
+		h.buf.WriteString(" (deprecated)")
+	}
+	h.buf.WriteString("\n")
+
+	var lines []string
+	if doc := FieldDoc(field); doc != "" {
+		lines = append(lines, h.style(styleDoc, h.text(pkgType, doc)))
+	}
+	if len(field.Enum) > 0 {
+		values := make([]string, 0, len(field.Enum))
+		for _, value := range field.Enum {
+			values = append(values, h.style(styleEnum, value))
+		}
+		lines = append(lines, "Allowed values: "+strings.Join(values, ", "))
+	}
+	if field.Example != "" {
+		lines = append(lines, "Example: "+field.Example)
+	}
+	if field.Deprecated != nil && field.Deprecated.Message != "" {
+		lines = append(lines, "Deprecated: "+h.text(pkgType, field.Deprecated.Message))
+	}
+	if pkgType.Union != nil {
+		if line := UnionAnnotation(pkgType.Union, field); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for _, line := range strings.Split(strings.Join(lines, "\n"), "\n") {
+		if line == "" {
+			h.buf.WriteString("\n")
+			continue
+		}
+		h.buf.WriteString("    " + line + "\n")
+	}
+}
+
+// text renders doc of pkgType or its fields to fit the indented help text.
+func (h *helpWriter) text(pkgType loader.Type, doc string) string {
+	r := h.renderer.In(pkgType.Package)
+	if r.TextWidth > 4 {
+		r.TextWidth -= 4
+	}
+	return strings.TrimSpace(r.Text(doc))
+}
+
+// style wraps each line of s in the escape sequence code if color is enabled.
+func (h *helpWriter) style(code, s string) string {
+	if !h.color || s == "" {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = code + line + styleReset
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// page shows out through the pager in the PAGER environment variable, or `less`, writing out to w
+// directly if no pager is available.
+func page(out []byte, w io.Writer) error {
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less", "-FRX"}
+	}
+	if _, err := exec.LookPath(pager[0]); err != nil {
+		_, err = w.Write(out)
+		return err
+	}
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = bytes.NewReader(out)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	return errors.Wrapf(cmd.Run(), "failed to run pager %s", pager[0])
+}
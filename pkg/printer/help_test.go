@@ -0,0 +1,38 @@
+package printer_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+	"github.com/jimmidyson/prettyconf/pkg/printer/testdata"
+)
+
+var _ = Describe("Help", func() {
+	It("lists every key with its type and doc", func() {
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "help_annotated.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrintHelp((*testdata.AnnotatedConfig)(nil), w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(w.String()).To(Equal(string(expected)))
+	})
+
+	It("colorizes output when requested", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrintHelp(testdata.AnnotatedConfig{}, w, logger, printer.WithColor(true))).To(Succeed())
+		Expect(w.String()).To(ContainSubstring("\x1b[1;36mtimeout\x1b[0m <duration> \x1b[1;33m(required)\x1b[0m"))
+		Expect(w.String()).To(ContainSubstring("Allowed values: \x1b[32mfast\x1b[0m, \x1b[32mslow\x1b[0m"))
+	})
+
+	It("wraps docs to the text width", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrintHelp(testdata.AnnotatedConfig{}, w, logger, printer.WithTextWidth(24))).To(Succeed())
+		Expect(w.String()).To(ContainSubstring("    labels are applied\n    to everything.\n"))
+	})
+})
@@ -0,0 +1,27 @@
+package printer
+
+import (
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// KeyPaths maps the names of the types reachable from root qualified by their package path, e.g.
+// `example.com/app.Server`, and the names of their fields qualified by those, e.g.
+// `example.com/app.Server.Port`, to the config key paths they are first found at, as used by
+// doccomment.Renderer. Slice elements are addressed as `key[*]` and map values as `key.*`.
+func KeyPaths(root loader.Type, packages []loader.Package) map[string]string {
+	keyPaths := map[string]string{}
+	loader.Walk(root, packages, nil, func(field *loader.WalkedField) bool {
+		fieldName := field.Owner.Package + "." + field.Owner.Name + "." + field.Name
+		if _, ok := keyPaths[fieldName]; !ok {
+			keyPaths[fieldName] = field.Path
+		}
+		if field.Elem != nil {
+			typeName := field.Elem.Package + "." + field.Elem.Name
+			if _, ok := keyPaths[typeName]; !ok {
+				keyPaths[typeName] = field.ElemPath
+			}
+		}
+		return true
+	})
+	return keyPaths
+}
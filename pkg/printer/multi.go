@@ -0,0 +1,110 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Section is a config printed under a top-level key of a combined document.
+type Section struct {
+	Key    string
+	Config interface{}
+}
+
+// PrettyPrintAll prints each of confs as a separate document of a YAML stream, separated by `---`.
+// Each document is headed by the doc of its type. The packages of all of the configs are loaded in
+// a single pass.
+func PrettyPrintAll(confs []interface{}, w io.Writer, logger logr.Logger, opts ...Option) error {
+	nodes, err := Nodes(confs, logger, opts...)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	for i, node := range nodes {
+		if err := encoder.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{node}}); err != nil {
+			return errors.Wrapf(err, "failed to marshal commented yaml node for document %d", i)
+		}
+	}
+	return errors.Wrap(encoder.Close(), "failed to marshal commented yaml nodes")
+}
+
+// PrettyPrintSections prints the configs as a single document with each config under its section
+// key. Each section is headed by the doc of its config's type. The packages of all of the configs
+// are loaded in a single pass.
+func PrettyPrintSections(sections []Section, w io.Writer, logger logr.Logger, opts ...Option) error {
+	node, err := SectionsNode(sections, logger, opts...)
+	if err != nil {
+		return err
+	}
+
+	marshalledConfig, err := yaml.Marshal(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{node}})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal commented yaml node")
+	}
+	fmt.Fprintln(w, string(marshalledConfig))
+
+	return nil
+}
+
+// Nodes returns the commented YAML mapping node for each of confs, as returned by Node. The
+// packages of all of the configs are loaded in a single pass.
+func Nodes(confs []interface{}, logger logr.Logger, opts ...Option) ([]*yaml.Node, error) {
+	confTypes := make([]reflect.Type, 0, len(confs))
+	for _, conf := range confs {
+		confTypes = append(confTypes, reflect.TypeOf(conf))
+	}
+	o := newOptions(opts)
+	astLoader, packages, err := loadPackages(confTypes, logger, o)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*yaml.Node, 0, len(confs))
+	for _, conf := range confs {
+		node, err := commentedNode(conf, astLoader, packages, logger, o)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to print %s", reflect.TypeOf(conf))
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// SectionsNode returns a commented YAML mapping node with the node of each config under its
+// section key. Section keys must be unique.
+func SectionsNode(sections []Section, logger logr.Logger, opts ...Option) (*yaml.Node, error) {
+	confs := make([]interface{}, 0, len(sections))
+	keys := map[string]bool{}
+	for _, section := range sections {
+		if keys[section.Key] {
+			return nil, errors.Errorf("duplicate section key %s", section.Key)
+		}
+		keys[section.Key] = true
+		confs = append(confs, section.Config)
+	}
+
+	nodes, err := Nodes(confs, logger, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	combined := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i, section := range sections {
+		keyNode := &yaml.Node{
+			Kind:        yaml.ScalarNode,
+			Tag:         "!!str",
+			Value:       section.Key,
+			HeadComment: strings.TrimSuffix(nodes[i].HeadComment, "\n\n"),
+		}
+		nodes[i].HeadComment = ""
+		combined.Content = append(combined.Content, keyNode, nodes[i])
+	}
+	return combined, nil
+}
@@ -0,0 +1,99 @@
+package printer_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+	"github.com/jimmidyson/prettyconf/pkg/printer/testdata"
+	"github.com/jimmidyson/prettyconf/pkg/registry"
+)
+
+var _ = Describe("Multiple configs", func() {
+	It("prints a YAML stream", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrintAll([]interface{}{
+			testdata.BStruct{G: "g"},
+			testdata.CStruct{H: "h"},
+		}, w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(`
+# BStruct holds B fields.
+
+# g comment.
+g: g
+---
+# CStruct holds C fields.
+
+# h comment.
+h: h`)))
+	})
+
+	It("prints sections", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrintSections([]printer.Section{
+			{Key: "b", Config: testdata.BStruct{G: "g"}},
+			{Key: "c", Config: testdata.CStruct{H: "h"}},
+		}, w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(`
+# BStruct holds B fields.
+b:
+    # g comment.
+    g: g
+# CStruct holds C fields.
+c:
+    # h comment.
+    h: h`)))
+	})
+
+	It("prints configs from the same package without source", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrintAll([]interface{}{
+			noSourceConfig{Name: "name"},
+			noSourceSection{Enabled: true},
+		}, w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(`
+name: name
+secret: <redacted>
+nested: null
+items: []
+---
+enabled: true`)))
+	})
+
+	It("rejects duplicate section keys", func() {
+		Expect(printer.PrettyPrintSections([]printer.Section{
+			{Key: "b", Config: testdata.BStruct{}},
+			{Key: "b", Config: testdata.CStruct{}},
+		}, &bytes.Buffer{}, logger)).NotTo(Succeed())
+	})
+
+	It("prints registered sections", func() {
+		reg := registry.New()
+		reg.MustRegister("c", testdata.CStruct{H: "h"})
+		reg.MustRegister("b", &testdata.BStruct{G: "g"})
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrintRegistry(reg, w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(`
+# BStruct holds B fields.
+b:
+    # g comment.
+    g: g
+# CStruct holds C fields.
+c:
+    # h comment.
+    h: h`)))
+	})
+})
+
+// noSourceSection is declared in a test package with noSourceConfig.
+type noSourceSection struct {
+	Enabled bool `json:"enabled"`
+}
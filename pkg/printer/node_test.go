@@ -0,0 +1,58 @@
+package printer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+	"github.com/jimmidyson/prettyconf/pkg/printer/testdata"
+)
+
+var _ = Describe("Node", func() {
+	conf := testdata.SecretConfig{
+		Name:   "admin",
+		Nested: []testdata.SecretHolder{{ID: "first", Token: "abc"}},
+	}
+
+	It("returns the commented mapping node", func() {
+		node, err := printer.Node(conf, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(node.Kind).To(Equal(yaml.MappingNode))
+		Expect(node.HeadComment).To(Equal("SecretConfig holds sensitive values.\n\n"))
+		Expect(node.Content[0].Value).To(Equal("name"))
+		Expect(node.Content[0].HeadComment).To(Equal("name is not sensitive."))
+	})
+
+	It("can be embedded in other documents", func() {
+		node, err := printer.Node(conf, logger)
+		Expect(err).NotTo(HaveOccurred())
+		wrapper := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "secrets"}, node,
+		}}
+		out, err := yaml.Marshal(wrapper)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(ContainSubstring("secrets:\n    # name is not sensitive.\n    name: admin\n"))
+	})
+
+	It("returns the node at a key path", func() {
+		node, err := printer.NodeAt(conf, "nested[0]", logger)
+		Expect(err).NotTo(HaveOccurred())
+		out, err := yaml.Marshal(node)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal("# id is not sensitive.\nid: first\n# token is sensitive.\ntoken: <redacted>\n"))
+	})
+
+	It("moves the key comment to the node at a key path", func() {
+		node, err := printer.NodeAt(conf, "byName", logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(node.HeadComment).To(Equal("byName maps names to sensitive values."))
+	})
+
+	It("errors for unknown key paths", func() {
+		_, err := printer.NodeAt(conf, "nested[1]", logger)
+		Expect(err).To(HaveOccurred())
+		_, err = printer.NodeAt(conf, "unknown", logger)
+		Expect(err).To(HaveOccurred())
+	})
+})
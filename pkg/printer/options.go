@@ -0,0 +1,90 @@
+package printer
+
+import "github.com/jimmidyson/prettyconf/pkg/loader"
+
+// Option configures how a config is printed.
+type Option func(*options)
+
+type options struct {
+	fieldOrder      loader.FieldOrder
+	typeAnnotations bool
+	hideDeprecated  bool
+	textWidth       int
+	color           *bool
+	pager           bool
+	packages        []loader.Package
+	keyComment      func(path string) string
+}
+
+func newOptions(opts []Option) options {
+	o := options{fieldOrder: loader.ExplicitOrder}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithFieldOrder sets the strategy used to order the keys of every printed mapping. The default is
+// loader.ExplicitOrder.
+func WithFieldOrder(order loader.FieldOrder) Option {
+	return func(o *options) {
+		o.fieldOrder = order
+	}
+}
+
+// WithTypeAnnotations appends a line to the comment of every field describing its type, whether it
+// is required and its allowed values, e.g. `Type: int32 | Required | Enum: a,b`.
+func WithTypeAnnotations() Option {
+	return func(o *options) {
+		o.typeAnnotations = true
+	}
+}
+
+// WithoutDeprecated omits deprecated fields from the output. By default deprecated fields are
+// printed with their deprecation message.
+func WithoutDeprecated() Option {
+	return func(o *options) {
+		o.hideDeprecated = true
+	}
+}
+
+// WithTextWidth sets the width that docs are wrapped to. The default is 80, or the width of the
+// terminal for PrintHelp.
+func WithTextWidth(width int) Option {
+	return func(o *options) {
+		o.textWidth = width
+	}
+}
+
+// WithColor enables or disables colorized PrintHelp output, overriding terminal and NO_COLOR
+// detection.
+func WithColor(enabled bool) Option {
+	return func(o *options) {
+		o.color = &enabled
+	}
+}
+
+// WithPager shows PrintHelp output through a pager when writing to a terminal.
+func WithPager() Option {
+	return func(o *options) {
+		o.pager = true
+	}
+}
+
+// WithPackages prints using the passed in packages instead of loading the source of the packages of
+// the printed configs, e.g. packages read from a catalog written by `prettyconf export`. The
+// implementations of interface fields are not listed as they require the package source.
+func WithPackages(packages []loader.Package) Option {
+	return func(o *options) {
+		o.packages = packages
+	}
+}
+
+// WithKeyComments sets the line comment of every leaf value to the comment returned by keyComment
+// for its key path, e.g. `server.port` or `servers[0].url`. Empty comments are not
+// set. Use it with layered.Origins.Comment to show where each value was loaded from.
+func WithKeyComments(keyComment func(path string) string) Option {
+	return func(o *options) {
+		o.keyComment = keyComment
+	}
+}
@@ -6,123 +6,273 @@ import (
 	"go/types"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
 	"github.com/jimmidyson/prettyconf/pkg/loader"
 )
 
+// RedactedValue is printed in place of the value of any sensitive field.
+const RedactedValue = "<redacted>"
+
 // PrettyPrint prints the passed in conf to the writer w, including all fields and comments if
-// parsed from the package.
-func PrettyPrint(conf interface{}, w io.Writer, logger logr.Logger) error {
+// parsed from the package. The values of sensitive fields are replaced with RedactedValue.
+//
+// If the source of the package cannot be loaded a warning is logged and the config is printed
+// without comments, using the fields found by reflection.
+func PrettyPrint(conf interface{}, w io.Writer, logger logr.Logger, opts ...Option) error {
+	node, err := Node(conf, logger, opts...)
+	if err != nil {
+		return err
+	}
+
+	marshalledConfig, err := yaml.Marshal(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{node}})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal commented yaml node")
+	}
+
+	fmt.Fprintln(w, string(marshalledConfig))
+
+	return nil
+}
+
+// Node returns the commented YAML mapping node for conf, as printed by PrettyPrint. The doc of the
+// type of conf is set as the head comment of the node. The node can be embedded in other YAML
+// documents or post-processed before marshalling.
+func Node(conf interface{}, logger logr.Logger, opts ...Option) (*yaml.Node, error) {
+	o := newOptions(opts)
 	confType := reflect.TypeOf(conf)
+	astLoader, packages, err := loadPackages([]reflect.Type{confType}, logger, o)
+	if err != nil {
+		return nil, err
+	}
+	return commentedNode(conf, astLoader, packages, logger, o)
+}
 
-	confTypePkgPath := confType.PkgPath()
+// NodeAt returns the commented YAML node for the value at the key path in conf, e.g. `server.tls`
+// or `servers[0]`. The comment of the key is set as the head comment of the node.
+func NodeAt(conf interface{}, path string, logger logr.Logger, opts ...Option) (*yaml.Node, error) {
+	node, err := Node(conf, logger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return node, nil
+	}
+
+	var keyNode *yaml.Node
+	for _, key := range loader.SplitKeyPath(path) {
+		keyNode, node = childNode(node, key)
+		if node == nil {
+			return nil, errors.Errorf("key path %s could not be found", path)
+		}
+	}
+	if keyNode != nil && keyNode.HeadComment != "" {
+		node.HeadComment = keyNode.HeadComment
+	}
+	return node, nil
+}
+
+// childNode returns the key and value nodes for key in a mapping node, or the item at the index
+// key in a sequence node.
+func childNode(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i], node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(key)
+		if err == nil && i >= 0 && i < len(node.Content) {
+			return nil, node.Content[i]
+		}
+	}
+	return nil, nil
+}
+
+// loadPackages loads the packages of all of the passed in types in a single pass. If the source of
+// the packages cannot be found or loaded a warning is logged and the packages are built by
+// reflection, in which case the returned loader is nil. Packages passed in with WithPackages are returned as is,
+// also with a nil loader.
+func loadPackages(confTypes []reflect.Type, logger logr.Logger, o options) (*loader.ASTLoader, []loader.Package, error) {
+	if o.packages != nil {
+		return nil, o.packages, nil
+	}
 
-	astLoader := loader.New([]string{confTypePkgPath}, logger)
+	pkgPaths := make([]string, 0, len(confTypes))
+	seen := map[string]bool{}
+	for _, confType := range confTypes {
+		confType = derefType(confType)
+		if pkgPath := confType.PkgPath(); !seen[pkgPath] {
+			seen[pkgPath] = true
+			pkgPaths = append(pkgPaths, pkgPath)
+		}
+	}
+
+	astLoader := loader.New(pkgPaths, logger)
 	packages, err := astLoader.Load()
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse package %s", confTypePkgPath)
+		packages, err = reflectPackages(logger, err, confTypes...)
+		return nil, packages, err
 	}
+	return astLoader, packages, nil
+}
+
+// reflectPackages builds the packages of confTypes by reflection after the source of their packages
+// failed to load with loadErr, logging a warning that comments are not printed. Errors other than
+// source errors, such as invalid markers or struct tags, are returned rather than hidden.
+func reflectPackages(logger logr.Logger, loadErr error, confTypes ...reflect.Type) ([]loader.Package, error) {
+	if !loader.IsSourceError(loadErr) {
+		return nil, loadErr
+	}
+
+	pkgPaths := make([]string, 0, len(confTypes))
+	for _, confType := range confTypes {
+		pkgPaths = append(pkgPaths, derefType(confType).PkgPath())
+	}
+	logger.Info("WARNING: unable to load package source, printing without comments",
+		"packages", pkgPaths, "error", loadErr.Error())
+
+	var packages []loader.Package
+	for _, confType := range confTypes {
+		confType = derefType(confType)
+		reflected, err := loader.FromReflect(confType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to reflect type %s.%s", confType.PkgPath(), confType.Name())
+		}
+		packages = mergePackages(packages, reflected...)
+	}
+	return packages, nil
+}
+
+// mergePackages returns merged with packages added, merging the types of packages with the same
+// path and skipping types that are already present. merged is not modified.
+func mergePackages(merged []loader.Package, packages ...loader.Package) []loader.Package {
+	merged = append([]loader.Package(nil), merged...)
+	indexes := make(map[string]int, len(merged))
+	for i, pkg := range merged {
+		indexes[pkg.Path] = i
+	}
+	for _, pkg := range packages {
+		i, ok := indexes[pkg.Path]
+		if !ok {
+			i = len(merged)
+			indexes[pkg.Path] = i
+			merged = append(merged, loader.Package{Path: pkg.Path, Doc: pkg.Doc})
+		}
+		for _, t := range pkg.Types {
+			if _, found := filterType(t.Name, merged[i]); !found {
+				merged[i].Types = append(merged[i].Types[:len(merged[i].Types):len(merged[i].Types)], t)
+			}
+		}
+	}
+	return merged
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// commentedNode marshals conf to a YAML node and comments it using the loaded packages.
+func commentedNode(conf interface{}, astLoader *loader.ASTLoader, packages []loader.Package, logger logr.Logger, o options) (*yaml.Node, error) {
+	confType := derefType(reflect.TypeOf(conf))
+
+	confTypePkgPath := confType.PkgPath()
 
 	pkg, found := filterPackage(confTypePkgPath, packages)
 	if !found {
-		return errors.Errorf("package %s could not be found", confTypePkgPath)
+		return nil, errors.Errorf("package %s could not be found", confTypePkgPath)
 	}
 	pkgType, found := filterType(confType.Name(), pkg)
 	if !found {
-		return errors.Errorf("type %s.%s could not be found", confTypePkgPath, confType.Name())
+		return nil, errors.Errorf("type %s.%s could not be found", confTypePkgPath, confType.Name())
 	}
 
 	marshalledConfig, err := json.Marshal(conf)
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal initial config to json")
+		return nil, errors.Wrap(err, "failed to marshal initial config to json")
 	}
 
 	var unmarshaledConfigToMap map[string]interface{}
 	if err := json.Unmarshal(marshalledConfig, &unmarshaledConfigToMap); err != nil {
-		return errors.Wrap(err, "failed to unmarshal config to map")
+		return nil, errors.Wrap(err, "failed to unmarshal config to map")
 	}
 
-	if err := zeroUnsetFields(unmarshaledConfigToMap, pkgType.Fields, packages); err != nil {
-		return errors.Wrapf(err, "failed to zero unset fields")
+	if err := zeroUnsetFields(unmarshaledConfigToMap, pkgType, packages); err != nil {
+		return nil, errors.Wrapf(err, "failed to zero unset fields")
 	}
 
 	marshalledConfig, err = yaml.Marshal(unmarshaledConfigToMap)
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal initial config to yaml")
+		return nil, errors.Wrap(err, "failed to marshal initial config to yaml")
 	}
 
 	var unmarshalledDocumentNode yaml.Node
 	if err := yaml.Unmarshal(marshalledConfig, &unmarshalledDocumentNode); err != nil {
-		return errors.Wrap(err, "failed to unmarshal initial config to yaml node")
+		return nil, errors.Wrap(err, "failed to unmarshal initial config to yaml node")
 	}
 
 	if unmarshalledDocumentNode.Kind != yaml.DocumentNode {
-		return errors.New("expected a single YAML document node")
+		return nil, errors.New("expected a single YAML document node")
 	}
 
 	if len(unmarshalledDocumentNode.Content) > 1 {
-		return errors.New("should only have one YAML node in document")
+		return nil, errors.New("should only have one YAML node in document")
 	}
 
 	currentNode := unmarshalledDocumentNode.Content[0]
 
+	renderer := &doccomment.Renderer{KeyPaths: KeyPaths(pkgType, packages), TextWidth: o.textWidth}
 	if pkgType.Doc != "" {
-		currentNode.HeadComment = pkgType.Doc + "\n\n"
+		currentNode.HeadComment = yamlComment(renderer.In(pkgType.Package).Text(pkgType.Doc)) + "\n\n"
 	}
 
-	if err := visitContentNodes(currentNode, pkgType, packages); err != nil {
-		return errors.Wrap(err,"failed to visit all nodes")
+	v := &visitor{loader: astLoader, packages: packages, logger: logger, renderer: renderer, options: o}
+	if err := v.visitContentNodes(currentNode, pkgType, reflect.ValueOf(conf)); err != nil {
+		return nil, errors.Wrap(err, "failed to visit all nodes")
 	}
-
-	marshalledConfig, err = yaml.Marshal(&unmarshalledDocumentNode)
-	if err != nil {
-		return errors.Wrap(err,"failed to marshal commented yaml node")
+	if o.keyComment != nil {
+		addKeyComments(currentNode, "", o.keyComment)
 	}
 
-	fmt.Fprintln(w, string(marshalledConfig))
-
-	return nil
+	return currentNode, nil
 }
 
-func zeroUnsetFields(unmarshaledConfigToMap map[string]interface{}, fields []loader.Field, packages []loader.Package) error {
-	for _, field := range fields {
+func zeroUnsetFields(unmarshaledConfigToMap map[string]interface{}, pkgType loader.Type, packages []loader.Package) error {
+	for _, field := range pkgType.Fields {
 		if _, ok := unmarshaledConfigToMap[field.JSONProperty]; !ok {
+			if pkgType.Union != nil {
+				// Only the members that are set are printed, as setting more would be invalid.
+				if _, isMember := pkgType.Union.Member(field.JSONProperty); isMember {
+					continue
+				}
+			}
 			zeroValue, err := zeroPropertyForType(field.Type)
 			if err != nil {
 				return errors.Wrapf(err, "failed to set zero property value for %s", field.Name)
 			}
 			unmarshaledConfigToMap[field.JSONProperty] = zeroValue
 		}
-		switch t := field.Type.(type) {
-		case *types.Named:
-			switch t.Underlying().(type) {
-			case *types.Struct:
-				fieldType, err := fieldPkgPathAndName(t, packages)
-				if err != nil {
-					return err
-				}
-				zeroUnsetFields(unmarshaledConfigToMap[field.JSONProperty].(map[string]interface{}), fieldType.Fields, packages)
-			}
-		case *types.Pointer:
-			switch t := t.Elem().(type) {
-			case *types.Struct:
-				fieldType, err := fieldPkgPathAndName(t, packages)
-				if err != nil {
-					return err
-				}
-				zeroUnsetFields(unmarshaledConfigToMap[field.JSONProperty].(map[string]interface{}), fieldType.Fields, packages)
-			default:
-				fieldType, err := fieldPkgPathAndName(t, packages)
-				if err != nil {
-					return err
-				}
-				zeroUnsetFields(unmarshaledConfigToMap[field.JSONProperty].(map[string]interface{}), fieldType.Fields, packages)
-			}
+		nestedMap, ok := unmarshaledConfigToMap[field.JSONProperty].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldType, found := loader.FindNamedType(packages, field.Type)
+		if !found {
+			continue
+		}
+		if err := zeroUnsetFields(nestedMap, fieldType, packages); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -150,12 +300,29 @@ func zeroPropertyForType(fieldType types.Type) (interface{}, error) {
 		return []struct{}{}, nil
 	case *types.Map, *types.Struct:
 		return map[string]interface{}{}, nil
+	case *types.Interface:
+		return nil, nil
 	default:
 		return nil, fmt.Errorf("unhandled node content type: %s", reflect.TypeOf(t))
 	}
 }
 
-func visitContentNodes(node *yaml.Node, pkgType loader.Type, packages []loader.Package) error {
+// visitor walks YAML nodes alongside the loaded types they were marshalled from, adding comments.
+type visitor struct {
+	// loader is nil if the packages were built by reflection.
+	loader   *loader.ASTLoader
+	packages []loader.Package
+	logger   logr.Logger
+	renderer *doccomment.Renderer
+	// loadedPackagePaths records the packages loaded on demand for dynamic types.
+	loadedPackagePaths map[string]bool
+	options
+}
+
+// visitContentNodes comments the keys of the mapping node, which was marshalled from value of type
+// pkgType. value is used to resolve the dynamic types of interface fields and may be invalid.
+func (v *visitor) visitContentNodes(node *yaml.Node, pkgType loader.Type, value reflect.Value) error {
+	value = indirect(value)
 	for i, contentNode := range node.Content {
 		if i%2 != 0 {
 			continue
@@ -168,26 +335,195 @@ func visitContentNodes(node *yaml.Node, pkgType loader.Type, packages []loader.P
 		if !found {
 			return errors.Errorf("failed to find field %s in type %s.%s", contentNodeName, pkgType.Package, pkgType.Name)
 		}
-		doc := field.Doc
-		if strings.HasPrefix(doc, field.Name + " ") {
-			doc = field.JSONProperty + doc[len(field.Name):]
+		comment, err := v.fieldComment(pkgType, field)
+		if err != nil {
+			return err
 		}
-		contentNode.HeadComment = doc
+		contentNode.HeadComment = comment
 		valueContentNode := node.Content[i+1]
-		if valueContentNode.Kind == yaml.MappingNode {
-			pkgType, err := fieldPkgPathAndName(field.Type, packages)
-			if err != nil {
-				return err
+		if field.Sensitive {
+			redactNode(valueContentNode)
+			continue
+		}
+		if err := v.visitValueNode(valueContentNode, field.Type, fieldByName(value, field.Name)); err != nil {
+			return err
+		}
+	}
+	node.Content = sortContentNodes(loader.SortFields(v.printedFields(pkgType.Fields), v.fieldOrder), node.Content)
+	addSectionHeaders(pkgType, node.Content)
+	return nil
+}
+
+// printedFields returns the fields that should be printed according to the options. Hidden fields
+// are never printed.
+func (v *visitor) printedFields(fields []loader.Field) []loader.Field {
+	printed := make([]loader.Field, 0, len(fields))
+	for _, field := range fields {
+		if field.Hidden || (v.hideDeprecated && field.Deprecated != nil) {
+			continue
+		}
+		printed = append(printed, field)
+	}
+	return printed
+}
+
+func (v *visitor) fieldComment(pkgType loader.Type, field loader.Field) (string, error) {
+	var lines []string
+	if doc := FieldDoc(field); doc != "" {
+		lines = append(lines, v.renderer.In(pkgType.Package).Text(doc))
+	}
+	if field.Example != "" {
+		lines = append(lines, "Example: "+field.Example)
+	}
+	if field.Deprecated != nil {
+		lines = append(lines, strings.TrimSpace("Deprecated: "+v.renderer.In(pkgType.Package).Text(field.Deprecated.Message)))
+	}
+	if pkgType.Union != nil {
+		if line := UnionAnnotation(pkgType.Union, field); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if iface, ok := interfaceType(field.Type); ok && v.loader != nil {
+		implementations, err := v.loader.Implementations(iface)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to list implementations for %s", field.Name)
+		}
+		if len(implementations) > 0 {
+			names := make([]string, 0, len(implementations))
+			for _, t := range implementations {
+				names = append(names, t.Name)
 			}
-			if err := visitContentNodes(valueContentNode, pkgType, packages); err != nil {
+			lines = append(lines, "Implementations: "+strings.Join(names, ", ")+".")
+		}
+	}
+	if v.typeAnnotations {
+		lines = append(lines, TypeAnnotation(field))
+	}
+	return yamlComment(strings.Join(lines, "\n")), nil
+}
+
+// yamlComment keeps blank lines in a multi-paragraph comment as empty comment lines, so that the
+// comment is printed as a single block.
+func yamlComment(comment string) string {
+	if comment == "" {
+		return ""
+	}
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = "#"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// interfaceType returns t, or the element type of t if it is a pointer, if it is an interface.
+func interfaceType(t types.Type) (types.Type, bool) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	_, ok := t.Underlying().(*types.Interface)
+	return t, ok
+}
+
+// addSectionHeaders prefixes the comment of the first key of each section with the section name.
+func addSectionHeaders(pkgType loader.Type, contentNodes []*yaml.Node) {
+	currentSection := ""
+	for i := 0; i < len(contentNodes); i += 2 {
+		field, found := filterField(contentNodes[i].Value, pkgType)
+		if !found || field.Section == currentSection {
+			continue
+		}
+		currentSection = field.Section
+		if currentSection == "" {
+			continue
+		}
+		header := "== " + currentSection + " =="
+		if contentNodes[i].HeadComment != "" {
+			header += "\n\n" + contentNodes[i].HeadComment
+		}
+		contentNodes[i].HeadComment = header
+	}
+}
+
+func (v *visitor) visitValueNode(node *yaml.Node, valueType types.Type, value reflect.Value) error {
+	switch t := valueType.(type) {
+	case *types.Pointer:
+		return v.visitValueNode(node, t.Elem(), indirect(value))
+	case *types.Named:
+		switch t.Underlying().(type) {
+		case *types.Struct:
+		case *types.Interface:
+			return v.visitDynamicValue(node, value)
+		default:
+			return v.visitValueNode(node, t.Underlying(), value)
+		}
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		pkgType, err := fieldPkgPathAndName(t, v.packages)
+		if err != nil {
+			return err
+		}
+		return v.visitContentNodes(node, pkgType, value)
+	case *types.Interface:
+		return v.visitDynamicValue(node, value)
+	case *types.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := v.visitValueNode(node.Content[i], t.Elem(), mapIndex(value, node.Content[i-1].Value)); err != nil {
 				return err
 			}
 		}
+	case *types.Slice:
+		return v.visitSequenceNode(node, t.Elem(), value)
+	case *types.Array:
+		return v.visitSequenceNode(node, t.Elem(), value)
+	}
+	return nil
+}
+
+func (v *visitor) visitSequenceNode(node *yaml.Node, elemType types.Type, value reflect.Value) error {
+	if node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for i, itemNode := range node.Content {
+		if err := v.visitValueNode(itemNode, elemType, index(value, i)); err != nil {
+			return err
+		}
 	}
-	node.Content = sortContentNodes(pkgType.Fields, node.Content)
 	return nil
 }
 
+// addKeyComments sets the line comment of every leaf value under node, which is at the key path
+// path, to the comment returned by keyComment. Empty mappings and sequences are leaves.
+func addKeyComments(node *yaml.Node, path string, keyComment func(path string) string) {
+	if (node.Kind != yaml.MappingNode && node.Kind != yaml.SequenceNode) || len(node.Content) == 0 {
+		if comment := keyComment(path); comment != "" {
+			node.LineComment = "# " + comment
+		}
+		return
+	}
+	for i := range node.Content {
+		switch {
+		case node.Kind == yaml.SequenceNode:
+			addKeyComments(node.Content[i], loader.IndexKeyPath(path, i), keyComment)
+		case i%2 != 0:
+			addKeyComments(node.Content[i], loader.JoinKeyPath(path, node.Content[i-1].Value), keyComment)
+		}
+	}
+}
+
+func redactNode(node *yaml.Node) {
+	node.Kind = yaml.ScalarNode
+	node.Tag = "!!str"
+	node.Style = 0
+	node.Value = RedactedValue
+	node.Content = nil
+}
+
 func sortContentNodes(fields []loader.Field, contentNodes []*yaml.Node) []*yaml.Node {
 	sortedContentNodes := make([]*yaml.Node, 0, len(contentNodes))
 	for _, field := range fields {
@@ -9,8 +9,12 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/model"
 	"github.com/jimmidyson/prettyconf/pkg/printer"
 	"github.com/jimmidyson/prettyconf/pkg/printer/testdata"
+	"github.com/jimmidyson/prettyconf/pkg/printer/testdata/invalid"
+	"github.com/jimmidyson/prettyconf/pkg/printer/testdata/plugin"
 )
 
 var _ = Describe("Printer", func() {
@@ -36,4 +40,203 @@ var _ = Describe("Printer", func() {
 		GinkgoWriter.Write(w.Bytes())
 		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
 	})
+
+	It("should redact sensitive fields", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_secrets.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			testdata.SecretConfig{
+				Name:     "admin",
+				Password: "hunter2",
+				Nested: []testdata.SecretHolder{
+					{ID: "first", Token: "abc"},
+					{ID: "second", Token: "def"},
+				},
+				ByName: map[string]testdata.SecretHolder{
+					"third": {ID: "third", Token: "ghi"},
+				},
+			},
+			w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(w.String()).NotTo(ContainSubstring("hunter2"))
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should honour prettyconf tags", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_presentation.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			testdata.PresentationConfig{
+				Port:     8080,
+				Name:     "instance",
+				Internal: "internal",
+			},
+			w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should apply the field order to nested types", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_toplevel_alphabetical.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			testdata.TopLevel{
+				A: testdata.AStruct{
+					D: 5,
+					E: testdata.NestedStruct{
+						F: "somestring",
+					},
+				},
+			},
+			w, logger, printer.WithFieldOrder(loader.AlphabeticalOrder))).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should annotate fields with their types", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_annotated.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			testdata.AnnotatedConfig{
+				Mode:    "fast",
+				Servers: []testdata.NestedStruct{{F: "server"}},
+			},
+			w, logger, printer.WithTypeAnnotations())).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should print using packages read from a catalog", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_annotated.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		packages, err := loader.New([]string{"github.com/jimmidyson/prettyconf/pkg/printer/testdata"}, logger).Load()
+		Expect(err).NotTo(HaveOccurred())
+		var catalog bytes.Buffer
+		Expect(model.New(packages).Write(&catalog)).To(Succeed())
+		read, err := model.Read(&catalog)
+		Expect(err).NotTo(HaveOccurred())
+		packages, err = read.LoaderPackages()
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			testdata.AnnotatedConfig{
+				Mode:    "fast",
+				Servers: []testdata.NestedStruct{{F: "server"}},
+			},
+			w, logger, printer.WithTypeAnnotations(), printer.WithPackages(packages))).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should document unions and interfaces", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_unions.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			testdata.PluginConfig{
+				Storage: testdata.StorageConfig{
+					Type: "s3",
+					S3:   &testdata.S3Backend{Bucket: "bucket"},
+				},
+			},
+			w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should document the dynamic types of interface values", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_dynamic.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			testdata.DynamicConfig{
+				Backend: &testdata.GCSBackend{Bucket: "bucket"},
+				Plugins: map[string]interface{}{
+					"first": plugin.Config{Endpoint: "localhost:1234", Retries: 3},
+				},
+			},
+			w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should mark deprecated fields", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(testdata.DeprecatedConfig{Listen: ":8080"}, w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(`
+# DeprecatedConfig holds deprecated fields.
+
+# address is the address to listen on.
+# Deprecated: Use Listen instead.
+address: ""
+# listen is the address to listen on.
+listen: :8080`)))
+	})
+
+	It("should hide deprecated fields", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(testdata.DeprecatedConfig{Listen: ":8080"}, w, logger, printer.WithoutDeprecated())).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(`
+# DeprecatedConfig holds deprecated fields.
+
+# listen is the address to listen on.
+listen: :8080`)))
+	})
+
+	It("should render doc comment syntax", func() {
+		desiredConfig, err := ioutil.ReadFile(filepath.Join("testdata", "printed_doc_syntax.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(testdata.DocSyntaxConfig{}, w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(string(desiredConfig))))
+	})
+
+	It("should print without comments when the source is unavailable", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(
+			noSourceConfig{
+				Name:   "name",
+				Secret: "hunter2",
+				Nested: &testdata.NestedStruct{F: "f"},
+			},
+			w, logger)).To(Succeed())
+		GinkgoWriter.Write(w.Bytes())
+		Expect(strings.TrimSpace(w.String())).To(Equal(strings.TrimSpace(`
+name: name
+secret: <redacted>
+nested:
+    f: f
+items: []`)))
+	})
+
+	It("should return invalid markers rather than printing without comments", func() {
+		w := &bytes.Buffer{}
+		Expect(printer.PrettyPrint(invalid.Config{}, w, logger)).
+			To(MatchError(ContainSubstring("invalid +order marker on field Config.Name")))
+		Expect(w.String()).To(BeEmpty())
+	})
 })
+
+// noSourceConfig is declared in a test package, which the loader cannot load from source.
+type noSourceConfig struct {
+	Name   string                 `json:"name"`
+	Secret string                 `json:"secret" prettyconf:"secret"`
+	Nested *testdata.NestedStruct `json:"nested"`
+	Items  []string               `json:"items,omitempty"`
+}
@@ -0,0 +1,32 @@
+package printer
+
+import (
+	"io"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jimmidyson/prettyconf/pkg/registry"
+)
+
+// PrettyPrintRegistry prints the combined application config made of the default values of the
+// sections registered in reg, each under its key and headed by the doc of its type.
+func PrettyPrintRegistry(reg *registry.Registry, w io.Writer, logger logr.Logger, opts ...Option) error {
+	return PrettyPrintSections(RegistrySections(reg), w, logger, opts...)
+}
+
+// RegistryNode returns the commented YAML mapping node for the combined application config made of
+// the default values of the sections registered in reg.
+func RegistryNode(reg *registry.Registry, logger logr.Logger, opts ...Option) (*yaml.Node, error) {
+	return SectionsNode(RegistrySections(reg), logger, opts...)
+}
+
+// RegistrySections returns the sections registered in reg with their default values.
+func RegistrySections(reg *registry.Registry) []Section {
+	entries := reg.Entries()
+	sections := make([]Section, 0, len(entries))
+	for _, e := range entries {
+		sections = append(sections, Section{Key: e.Key, Config: e.Default})
+	}
+	return sections
+}
@@ -0,0 +1,14 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package printer
+
+import (
+	"os"
+	"strconv"
+)
+
+// terminalWidth returns the width from the COLUMNS environment variable, or zero if it is not set.
+func terminalWidth(_ *os.File) int {
+	columns, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return columns
+}
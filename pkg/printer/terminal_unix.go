@@ -0,0 +1,20 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package printer
+
+import (
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalWidth returns the width of the terminal f, falling back to the COLUMNS environment
+// variable, or zero if the width is unknown.
+func terminalWidth(f *os.File) int {
+	if ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ); err == nil && ws.Col > 0 {
+		return int(ws.Col)
+	}
+	columns, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return columns
+}
@@ -0,0 +1,9 @@
+package plugin
+
+// Config configures the plugin.
+type Config struct {
+	// Endpoint is the plugin endpoint.
+	Endpoint string `json:"endpoint"`
+	// Retries is the number of retries.
+	Retries int `json:"retries,omitempty"`
+}
@@ -1,5 +1,7 @@
 package testdata
 
+import "time"
+
 // TopLevel holds the details for top level config.
 type TopLevel struct {
 	// A is field for AStruct.
@@ -36,3 +38,136 @@ type CStruct struct {
 	// H comment.
 	H string `json:"h,omitempty"`
 }
+
+// SecretConfig holds sensitive values.
+type SecretConfig struct {
+	// Name is not sensitive.
+	Name string `json:"name"`
+	// Password is sensitive.
+	// +sensitive
+	Password string `json:"password"`
+	// Nested holds nested sensitive values.
+	Nested []SecretHolder `json:"nested"`
+	// ByName maps names to sensitive values.
+	ByName map[string]SecretHolder `json:"byName"`
+}
+
+// SecretHolder holds a secret.
+type SecretHolder struct {
+	// ID is not sensitive.
+	ID string `json:"id"`
+	// Token is sensitive.
+	Token string `json:"token" prettyconf:"secret"`
+}
+
+// PresentationConfig holds fields controlled by the prettyconf tag.
+type PresentationConfig struct {
+	// Port is the port to listen on.
+	Port int `json:"port" prettyconf:"order=2,section=Server,example=8080"`
+	// Name is the name of the instance.
+	Name string `json:"name" prettyconf:"order=-1"`
+	// Host is the host to listen on. It may be empty.
+	Host string `json:"host" prettyconf:"order=2,section=Server,summary=The listen host."`
+	// Internal is never printed.
+	Internal string `json:"internal" prettyconf:"hidden"`
+	// Debug enables debug logging.
+	Debug bool `json:"debug" prettyconf:"order=3,section=Logging"`
+}
+
+// AnnotatedConfig holds fields of various types.
+type AnnotatedConfig struct {
+	// Timeout is how long to wait.
+	Timeout time.Duration `json:"timeout"`
+	// Mode is the mode to run in.
+	// +enum=fast;slow
+	Mode string `json:"mode,omitempty"`
+	// Labels are applied to everything.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Servers to connect to.
+	Servers []NestedStruct `json:"servers"`
+}
+
+// Backend is implemented by backend configs.
+type Backend interface {
+	backend()
+}
+
+// S3Backend configures S3.
+type S3Backend struct {
+	// Bucket is the bucket name.
+	Bucket string `json:"bucket"`
+}
+
+func (S3Backend) backend() {}
+
+// GCSBackend configures GCS.
+type GCSBackend struct {
+	// Bucket is the bucket name.
+	Bucket string `json:"bucket"`
+}
+
+func (*GCSBackend) backend() {}
+
+// StorageConfig configures one storage backend.
+// +union:discriminator=type
+type StorageConfig struct {
+	// Type selects the backend.
+	Type string `json:"type"`
+	// S3 configures S3 storage.
+	// +union:member=s3
+	S3 *S3Backend `json:"s3,omitempty"`
+	// GCS configures GCS storage.
+	// +union:member=gcs
+	GCS *GCSBackend `json:"gcs,omitempty"`
+}
+
+// PluginConfig holds unions and interfaces.
+type PluginConfig struct {
+	// Storage configures storage.
+	Storage StorageConfig `json:"storage"`
+	// Backend is the plugin backend.
+	Backend Backend `json:"backend"`
+	// Fallback is the backend used when the plugin backend fails.
+	Fallback *Backend `json:"fallback,omitempty"`
+}
+
+// DynamicConfig holds interface values.
+type DynamicConfig struct {
+	// Backend is the storage backend.
+	Backend Backend `json:"backend"`
+	// Plugins configure plugins by name.
+	Plugins map[string]interface{} `json:"plugins"`
+}
+
+// DeprecatedConfig holds deprecated fields.
+type DeprecatedConfig struct {
+	// Address is the address to listen on.
+	//
+	// Deprecated: Use Listen instead.
+	Address string `json:"address,omitempty"`
+	// Listen is the address to listen on.
+	Listen string `json:"listen"`
+}
+
+// DocSyntaxConfig shows doc comment syntax, see
+// [DocSyntaxConfig.Server].
+type DocSyntaxConfig struct {
+	// Server configures the server. Its address is set
+	// with [ServerSettings.Address].
+	//
+	// Modes:
+	//   - fast
+	//   - slow
+	//
+	// For example:
+	//
+	//	server:
+	//	  address: localhost
+	Server ServerSettings `json:"server"`
+}
+
+// ServerSettings holds server settings.
+type ServerSettings struct {
+	// Address is the address. It is used by [ServerSettings].
+	Address string `json:"address"`
+}
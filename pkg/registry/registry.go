@@ -0,0 +1,85 @@
+// Package registry holds the config sections contributed by the modules of an application, so that
+// the combined application config can be printed and documented in one place.
+package registry
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Entry is a config section registered by a module.
+type Entry struct {
+	// Key is the top-level key the section is found under.
+	Key string
+	// Type is the type of the section's config.
+	Type reflect.Type
+	// Default is the default value of the section's config.
+	Default interface{}
+}
+
+// Registry holds registered config sections. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{entries: map[string]Entry{}}
+}
+
+// Register registers the config section key with its default value. The type of the section is
+// the type of the default value. It returns an error if key is already registered.
+func (r *Registry) Register(key string, defaultValue interface{}) error {
+	if key == "" {
+		return errors.New("section key must not be empty")
+	}
+	if defaultValue == nil {
+		return errors.Errorf("default value for section %s must not be nil", key)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.entries[key]; ok {
+		return errors.Errorf("section %s is already registered with type %s", key, existing.Type)
+	}
+	r.entries[key] = Entry{Key: key, Type: reflect.TypeOf(defaultValue), Default: defaultValue}
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is intended to be called from init.
+func (r *Registry) MustRegister(key string, defaultValue interface{}) {
+	if err := r.Register(key, defaultValue); err != nil {
+		panic(err)
+	}
+}
+
+// Entries returns the registered sections sorted by key.
+func (r *Registry) Entries() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entries := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// Default is the registry used by the package level Register and MustRegister functions.
+var Default = New()
+
+// Register registers a config section with the Default registry.
+func Register(key string, defaultValue interface{}) error {
+	return Default.Register(key, defaultValue)
+}
+
+// MustRegister registers a config section with the Default registry, panicking on error.
+func MustRegister(key string, defaultValue interface{}) {
+	Default.MustRegister(key, defaultValue)
+}
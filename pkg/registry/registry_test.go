@@ -0,0 +1,46 @@
+package registry_test
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/registry"
+)
+
+type serverConfig struct {
+	Port int
+}
+
+type clientConfig struct {
+	Timeout int
+}
+
+var _ = Describe("Registry", func() {
+	var reg *registry.Registry
+
+	BeforeEach(func() {
+		reg = registry.New()
+	})
+
+	It("returns entries sorted by key", func() {
+		Expect(reg.Register("server", serverConfig{Port: 8080})).To(Succeed())
+		Expect(reg.Register("client", &clientConfig{})).To(Succeed())
+		Expect(reg.Entries()).To(Equal([]registry.Entry{
+			{Key: "client", Type: reflect.TypeOf(&clientConfig{}), Default: &clientConfig{}},
+			{Key: "server", Type: reflect.TypeOf(serverConfig{}), Default: serverConfig{Port: 8080}},
+		}))
+	})
+
+	It("rejects duplicate keys", func() {
+		Expect(reg.Register("server", serverConfig{})).To(Succeed())
+		Expect(reg.Register("server", clientConfig{})).NotTo(Succeed())
+		Expect(func() { reg.MustRegister("server", clientConfig{}) }).To(Panic())
+	})
+
+	It("rejects empty keys and nil defaults", func() {
+		Expect(reg.Register("", serverConfig{})).NotTo(Succeed())
+		Expect(reg.Register("server", nil)).NotTo(Succeed())
+	})
+})
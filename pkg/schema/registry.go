@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+	"github.com/jimmidyson/prettyconf/pkg/registry"
+)
+
+// ForRegistry returns the schema of the combined application config made of the sections
+// registered in reg, with each section under its key and described by the doc of its type. The
+// packages of all of the sections are loaded in a single pass.
+func ForRegistry(reg *registry.Registry, logger logr.Logger) (*Schema, error) {
+	entries := reg.Entries()
+	pkgPaths := make([]string, 0, len(entries))
+	seen := map[string]bool{}
+	for _, e := range entries {
+		if pkgPath := derefType(e.Type).PkgPath(); !seen[pkgPath] {
+			seen[pkgPath] = true
+			pkgPaths = append(pkgPaths, pkgPath)
+		}
+	}
+	var packages []loader.Package
+	if len(pkgPaths) > 0 {
+		var err error
+		packages, err = loader.New(pkgPaths, logger).Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the packages of registered sections")
+		}
+	}
+
+	sectionTypes := make([]loader.Type, 0, len(entries))
+	keyPaths := map[string]string{}
+	for _, e := range entries {
+		t := derefType(e.Type)
+		sectionType, found := loader.FindType(packages, t.PkgPath(), t.Name())
+		if !found {
+			return nil, errors.Errorf("type %s of section %s could not be found", t, e.Key)
+		}
+		sectionTypes = append(sectionTypes, sectionType)
+
+		// Doc links resolve to the section a type is first found in.
+		typeName := sectionType.Package + "." + sectionType.Name
+		if _, ok := keyPaths[typeName]; !ok {
+			keyPaths[typeName] = e.Key
+		}
+		for name, keyPath := range printer.KeyPaths(sectionType, packages) {
+			if _, ok := keyPaths[name]; !ok {
+				keyPaths[name] = e.Key + "." + keyPath
+			}
+		}
+	}
+
+	g := newGenerator(packages, keyPaths)
+	root := &Schema{Schema: Draft, Type: "object", Properties: map[string]*Schema{}}
+	for i, e := range entries {
+		section := g.ref(sectionTypes[i])
+		section.Description = g.renderer.In(sectionTypes[i].Package).Text(sectionTypes[i].Doc)
+		root.Properties[e.Key] = section
+	}
+	root.Defs = g.defs
+	return root, nil
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
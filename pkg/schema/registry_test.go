@@ -0,0 +1,42 @@
+package schema_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/registry"
+	"github.com/jimmidyson/prettyconf/pkg/schema"
+	"github.com/jimmidyson/prettyconf/pkg/schema/testdata"
+)
+
+var _ = Describe("ForRegistry", func() {
+	It("describes each registered section under its key", func() {
+		reg := registry.New()
+		Expect(reg.Register("app", testdata.Config{})).To(Succeed())
+		Expect(reg.Register("backend", &testdata.Backend{})).To(Succeed())
+
+		s, err := schema.ForRegistry(reg, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Schema).To(Equal(schema.Draft))
+		Expect(s.Type).To(Equal("object"))
+		Expect(s.Properties).To(Equal(map[string]*schema.Schema{
+			"app": {
+				Ref:         "#/$defs/github.com.jimmidyson.prettyconf.pkg.schema.testdata.Config",
+				Description: "Config is the app config.",
+			},
+			"backend": {
+				Ref:         "#/$defs/github.com.jimmidyson.prettyconf.pkg.schema.testdata.Backend",
+				Description: "Backend is a backend server.",
+			},
+		}))
+		Expect(s.Defs).To(HaveKey("github.com.jimmidyson.prettyconf.pkg.schema.testdata.Storage"))
+		Expect(s.Defs["github.com.jimmidyson.prettyconf.pkg.schema.testdata.Config"].Properties["storage"].Description).
+			To(Equal("storage is where data is kept. See app.storage.type."))
+	})
+
+	It("writes an empty object for an empty registry", func() {
+		s, err := schema.ForRegistry(registry.New(), logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Properties).To(BeEmpty())
+	})
+})
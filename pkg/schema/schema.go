@@ -0,0 +1,213 @@
+// Package schema generates JSON Schemas for config types, for editors and tools that validate
+// config documents without loading Go source.
+package schema
+
+import (
+	"encoding/json"
+	"go/types"
+	"io"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	// ContentEncoding is set for byte slices, which are serialized as base64 strings.
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Const                string             `json:"const,omitempty"`
+	// OneOf holds a schema for each member of a union, each of which requires the member.
+	OneOf      []*Schema          `json:"oneOf,omitempty"`
+	Deprecated bool               `json:"deprecated,omitempty"`
+	Defs       map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// For loads the type of conf and returns its schema. See New.
+func For(conf interface{}, logger logr.Logger) (*Schema, error) {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return nil, err
+	}
+	return New(rootType, packages), nil
+}
+
+// New returns the schema of rootType. Types referenced by rootType are looked up in packages and
+// defined once in $defs, named by their package path with slashes replaced by dots and their name.
+// Hidden fields are left out. Unions require exactly one of their members with oneOf, and the
+// discriminator, if any, to select the member that is set.
+func New(rootType loader.Type, packages []loader.Package) *Schema {
+	g := newGenerator(packages, printer.KeyPaths(rootType, packages))
+	root := g.ref(rootType)
+	root.Schema = Draft
+	root.Defs = g.defs
+	return root
+}
+
+// Write writes the schema of rootType to w as indented JSON. See New.
+func Write(w io.Writer, rootType loader.Type, packages []loader.Package) error {
+	return New(rootType, packages).Write(w)
+}
+
+// Write writes the schema to w as indented JSON.
+func (s *Schema) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(s), "failed to write schema")
+}
+
+type generator struct {
+	packages []loader.Package
+	renderer *doccomment.Renderer
+	defs     map[string]*Schema
+	// visiting holds the named types whose underlying types are being described, so that
+	// recursive types that are not loaded terminate.
+	visiting map[*types.Named]bool
+}
+
+func newGenerator(packages []loader.Package, keyPaths map[string]string) *generator {
+	return &generator{
+		packages: packages,
+		renderer: &doccomment.Renderer{KeyPaths: keyPaths, TextWidth: -1},
+		defs:     map[string]*Schema{},
+		visiting: map[*types.Named]bool{},
+	}
+}
+
+// defName returns the name that the schema of t is defined under in $defs.
+func defName(t loader.Type) string {
+	return strings.ReplaceAll(t.Package, "/", ".") + "." + t.Name
+}
+
+// ref returns a reference to the definition of pkgType, adding the definition if needed.
+func (g *generator) ref(pkgType loader.Type) *Schema {
+	name := defName(pkgType)
+	if _, ok := g.defs[name]; !ok {
+		// The definition is added before its fields so that recursive types refer to it.
+		def := &Schema{Title: pkgType.Name, Type: "object", Deprecated: pkgType.Deprecated != nil}
+		g.defs[name] = def
+		g.object(def, pkgType)
+	}
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+func (g *generator) object(def *Schema, pkgType loader.Type) {
+	def.Description = g.renderer.In(pkgType.Package).Text(pkgType.Doc)
+	for _, field := range pkgType.Fields {
+		if field.Hidden || field.JSONProperty == "" {
+			continue
+		}
+		property := g.value(field.Type)
+		property.Description = g.renderer.In(pkgType.Package).Text(printer.FieldDoc(field))
+		property.Enum = field.Enum
+		property.Deprecated = field.Deprecated != nil
+		if def.Properties == nil {
+			def.Properties = map[string]*Schema{}
+		}
+		def.Properties[field.JSONProperty] = property
+
+		_, isMember := unionMember(pkgType.Union, field)
+		if field.JSONRequired && !isMember {
+			def.Required = append(def.Required, field.JSONProperty)
+		}
+	}
+	if pkgType.Union != nil {
+		g.union(def, pkgType.Union)
+	}
+}
+
+// union requires exactly one member of union to be set, and the discriminator to select it.
+func (g *generator) union(def *Schema, union *loader.Union) {
+	if union.Discriminator != "" {
+		if discriminator, ok := def.Properties[union.Discriminator]; ok {
+			discriminator.Enum = union.Values()
+		}
+	}
+	for _, member := range union.Members {
+		variant := &Schema{Required: []string{member.JSONProperty}}
+		if union.Discriminator != "" {
+			variant.Properties = map[string]*Schema{union.Discriminator: {Const: member.Value}}
+			variant.Required = append(variant.Required, union.Discriminator)
+		}
+		def.OneOf = append(def.OneOf, variant)
+	}
+}
+
+func unionMember(union *loader.Union, field loader.Field) (loader.UnionMember, bool) {
+	if union == nil {
+		return loader.UnionMember{}, false
+	}
+	return union.Member(field.JSONProperty)
+}
+
+// value returns the schema of values of type t, as they are serialized by encoding/json.
+func (g *generator) value(t types.Type) *Schema {
+	if pkgType, found := loader.FindNamedType(g.packages, t); found {
+		return g.ref(pkgType)
+	}
+	switch typ := t.(type) {
+	case *types.Pointer:
+		return g.value(typ.Elem())
+	case *types.Named:
+		if pkg := typ.Obj().Pkg(); pkg != nil && pkg.Path() == "time" && typ.Obj().Name() == "Time" {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		methods := types.NewMethodSet(types.NewPointer(typ))
+		if methods.Lookup(nil, "UnmarshalJSON") != nil {
+			return &Schema{}
+		}
+		if methods.Lookup(nil, "UnmarshalText") != nil {
+			return &Schema{Type: "string"}
+		}
+		if _, ok := typ.Underlying().(*types.Struct); ok || g.visiting[typ] {
+			return &Schema{Type: "object"}
+		}
+		g.visiting[typ] = true
+		defer delete(g.visiting, typ)
+		return g.value(typ.Underlying())
+	case *types.Basic:
+		switch {
+		case typ.Info()&types.IsBoolean != 0:
+			return &Schema{Type: "boolean"}
+		case typ.Info()&types.IsInteger != 0:
+			return &Schema{Type: "integer"}
+		case typ.Info()&types.IsFloat != 0:
+			return &Schema{Type: "number"}
+		case typ.Info()&types.IsString != 0:
+			return &Schema{Type: "string"}
+		default:
+			return &Schema{}
+		}
+	case *types.Slice:
+		if basic, ok := typ.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: g.value(typ.Elem())}
+	case *types.Array:
+		return &Schema{Type: "array", Items: g.value(typ.Elem())}
+	case *types.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.value(typ.Elem())}
+	case *types.Struct:
+		return &Schema{Type: "object"}
+	default:
+		return &Schema{}
+	}
+}
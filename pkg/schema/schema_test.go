@@ -0,0 +1,46 @@
+package schema_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/schema"
+	"github.com/jimmidyson/prettyconf/pkg/schema/testdata"
+)
+
+var _ = Describe("Schema", func() {
+	It("writes the schema of a config", func() {
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "schema.json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		s, err := schema.For(testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		var buf bytes.Buffer
+		Expect(s.Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(Equal(string(expected)))
+	})
+
+	It("requires exactly one member of unions, selected by the discriminator", func() {
+		s, err := schema.For(testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		storage := s.Defs["github.com.jimmidyson.prettyconf.pkg.schema.testdata.Storage"]
+		Expect(storage).NotTo(BeNil())
+		Expect(storage.Required).To(Equal([]string{"type"}))
+		Expect(storage.Properties["type"].Enum).To(Equal([]string{"s3", "gcs"}))
+		Expect(storage.OneOf).To(Equal([]*schema.Schema{
+			{Properties: map[string]*schema.Schema{"type": {Const: "s3"}}, Required: []string{"s3", "type"}},
+			{Properties: map[string]*schema.Schema{"type": {Const: "gcs"}}, Required: []string{"gcs", "type"}},
+		}))
+	})
+
+	It("leaves out hidden fields", func() {
+		s, err := schema.For(testdata.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Defs["github.com.jimmidyson.prettyconf.pkg.schema.testdata.Config"].Properties).NotTo(HaveKey("internal"))
+	})
+})
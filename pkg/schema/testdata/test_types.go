@@ -0,0 +1,53 @@
+package testdata
+
+import "time"
+
+// Config is the app config.
+type Config struct {
+	// Name is the name of the app.
+	Name string `json:"name"`
+	// Mode is the mode the app runs in.
+	// +enum=dev,prod
+	Mode string `json:"mode,omitempty"`
+	// Timeout bounds requests.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Labels are added to every metric.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Storage is where data is kept. See [Storage.Type].
+	Storage Storage `json:"storage"`
+	// Backends receive forwarded requests.
+	Backends []*Backend `json:"backends,omitempty"`
+	// Extra is passed through to plugins.
+	Extra interface{} `json:"extra,omitempty"`
+	// Port is the port to listen on.
+	//
+	// Deprecated: use Backends.
+	Port int `json:"port,omitempty"`
+	// Internal is only set by tests.
+	Internal bool `json:"internal,omitempty" prettyconf:"hidden"`
+}
+
+// Storage is where data is kept.
+// +union:discriminator=type
+type Storage struct {
+	// Type selects the storage backend.
+	Type string `json:"type"`
+	// +union:member=s3
+	S3 *Bucket `json:"s3,omitempty"`
+	// +union:member=gcs
+	GCS *Bucket `json:"gcs,omitempty"`
+}
+
+// Bucket is an object storage bucket.
+type Bucket struct {
+	// Name is the name of the bucket.
+	Name string `json:"name"`
+}
+
+// Backend is a backend server.
+type Backend struct {
+	// URL is the URL of the backend.
+	URL string `json:"url"`
+	// Weight is the share of requests sent to the backend.
+	Weight float64 `json:"weight,omitempty"`
+}
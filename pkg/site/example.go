@@ -0,0 +1,118 @@
+package site
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+// exampleYAML returns an example config for rootType, commented with the docs of its keys. Keys
+// are set to their example, their first allowed value or their zero value. Lists of objects have a
+// single item and hidden and deprecated keys are left out.
+func exampleYAML(rootType loader.Type, packages []loader.Package, order loader.FieldOrder) (string, error) {
+	e := &exampleBuilder{
+		packages: packages,
+		renderer: &doccomment.Renderer{KeyPaths: printer.KeyPaths(rootType, packages)},
+		order:    order,
+	}
+	node := e.mapping(rootType, map[string]bool{})
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal example config")
+	}
+	return string(out), nil
+}
+
+type exampleBuilder struct {
+	packages []loader.Package
+	renderer *doccomment.Renderer
+	order    loader.FieldOrder
+}
+
+func (e *exampleBuilder) mapping(pkgType loader.Type, visited map[string]bool) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	qualifiedName := pkgType.Package + "." + pkgType.Name
+	if visited[qualifiedName] {
+		node.Style = yaml.FlowStyle
+		return node
+	}
+	visited[qualifiedName] = true
+	defer delete(visited, qualifiedName)
+
+	for _, field := range loader.SortFields(pkgType.Fields, e.order) {
+		if field.Hidden || field.Deprecated != nil || field.JSONProperty == "" {
+			continue
+		}
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.JSONProperty}
+		if doc := e.renderer.In(pkgType.Package).Text(printer.FieldDoc(field)); doc != "" {
+			keyNode.HeadComment = comment(doc)
+		}
+		example := field.Example
+		if example == "" && len(field.Enum) > 0 {
+			example = field.Enum[0]
+		}
+		node.Content = append(node.Content, keyNode, e.value(field.Type, example, visited))
+	}
+	return node
+}
+
+func (e *exampleBuilder) value(t types.Type, example string, visited map[string]bool) *yaml.Node {
+	if example != "" {
+		var exampleNode yaml.Node
+		if err := yaml.Unmarshal([]byte(example), &exampleNode); err == nil && len(exampleNode.Content) == 1 {
+			return exampleNode.Content[0]
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: example}
+	}
+	if pkgType, found := loader.FindNamedType(e.packages, t); found {
+		return e.mapping(pkgType, visited)
+	}
+
+	switch typ := t.(type) {
+	case *types.Pointer:
+		return e.value(typ.Elem(), "", visited)
+	case *types.Named:
+		if pkg := typ.Obj().Pkg(); pkg != nil && pkg.Path() == "time" && typ.Obj().Name() == "Duration" {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "0s"}
+		}
+		return e.value(typ.Underlying(), "", visited)
+	case *types.Basic:
+		switch {
+		case typ.Info()&types.IsBoolean != 0:
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+		case typ.Info()&types.IsNumeric != 0:
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"}
+		default:
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "", Style: yaml.DoubleQuotedStyle}
+		}
+	case *types.Slice, *types.Array:
+		elemType, _ := loader.ElementType(t, "")
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if pkgType, found := loader.FindNamedType(e.packages, elemType); found {
+			node.Content = append(node.Content, e.mapping(pkgType, visited))
+		} else {
+			node.Style = yaml.FlowStyle
+		}
+		return node
+	default:
+		return &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	}
+}
+
+func comment(doc string) string {
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = "#"
+		} else {
+			lines[i] = "# " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,272 @@
+// Package site generates a self-contained static HTML reference for a config type, with a
+// collapsible tree of its keys, client-side search, links between keys and types, links to source
+// positions and an example config.
+package site
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/doccomment"
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+// IndexFile is the name of the page written by Generate.
+const IndexFile = "index.html"
+
+// Option configures a generated site.
+type Option func(*options)
+
+type options struct {
+	title     string
+	sourceURL func(pkgPath string, position loader.Position) string
+	order     loader.FieldOrder
+}
+
+// WithTitle sets the title of the page. The default is `<type name> configuration reference`.
+func WithTitle(title string) Option {
+	return func(o *options) {
+		o.title = title
+	}
+}
+
+// WithSourceURL sets the function returning the URL that the source position of a type or field
+// links to, e.g. a link to a line of a file in a code browser. Positions without a URL are shown
+// without a link. By default Generate links to source files by their path relative to the site
+// directory and Write does not link to source files.
+func WithSourceURL(sourceURL func(pkgPath string, position loader.Position) string) Option {
+	return func(o *options) {
+		o.sourceURL = sourceURL
+	}
+}
+
+// WithFieldOrder sets the order of the keys of each type. The default is loader.ExplicitOrder.
+func WithFieldOrder(order loader.FieldOrder) Option {
+	return func(o *options) {
+		o.order = order
+	}
+}
+
+// Generate writes the reference of rootType to IndexFile in dir, creating dir if it does not exist.
+// Types referenced by rootType are looked up in packages. The page has no external assets so the
+// directory can be served or opened offline.
+func Generate(dir string, rootType loader.Type, packages []loader.Package, opts ...Option) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.Wrapf(err, "invalid directory %s", dir)
+	}
+	relativeSourceURL := func(pkgPath string, position loader.Position) string {
+		if !filepath.IsAbs(position.Filename) {
+			return ""
+		}
+		rel, err := filepath.Rel(absDir, position.Filename)
+		if err != nil {
+			return ""
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, rootType, packages, append([]Option{WithSourceURL(relativeSourceURL)}, opts...)...); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %s", dir)
+	}
+	return errors.Wrapf(ioutil.WriteFile(filepath.Join(dir, IndexFile), buf.Bytes(), 0o644), "failed to write %s", IndexFile)
+}
+
+// Write writes the reference page of rootType to w. See Generate.
+func Write(w io.Writer, rootType loader.Type, packages []loader.Package, opts ...Option) error {
+	o := options{title: rootType.Name + " configuration reference", order: loader.ExplicitOrder}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	b := &builder{
+		packages: packages,
+		renderer: &doccomment.Renderer{KeyPaths: printer.KeyPaths(rootType, packages)},
+		options:  o,
+		usedAt:   map[string][]link{},
+	}
+	p := page{
+		Title:  o.title,
+		Doc:    b.html(rootType.Package, rootType.Doc),
+		Source: b.source(rootType.Package, rootType.Position),
+		Keys:   b.keys(rootType),
+	}
+
+	example, err := exampleYAML(rootType, packages, o.order)
+	if err != nil {
+		return err
+	}
+	p.Example = example
+
+	for _, pkg := range packages {
+		for _, t := range pkg.Types {
+			usedAt, used := b.usedAt[typeAnchor(t)]
+			if !used && !(t.Package == rootType.Package && t.Name == rootType.Name) {
+				continue
+			}
+			entry := typeEntry{
+				Name:       t.Name,
+				Package:    t.Package,
+				Anchor:     typeAnchor(t),
+				Doc:        b.html(t.Package, t.Doc),
+				Deprecated: t.Deprecated != nil,
+				Source:     b.source(t.Package, t.Position),
+				UsedAt:     usedAt,
+			}
+			if t.Deprecated != nil {
+				entry.DeprecationMessage = t.Deprecated.Message
+			}
+			p.Types = append(p.Types, entry)
+		}
+	}
+
+	return errors.Wrap(pageTemplate.Execute(w, p), "failed to write site")
+}
+
+type page struct {
+	Title   string
+	Doc     template.HTML
+	Source  source
+	Keys    []*key
+	Example string
+	Types   []typeEntry
+}
+
+// key is a key of the config, with the keys of its nested type as children.
+type key struct {
+	Path   string
+	Anchor string
+	// Type is the friendly name of the type of the key, linked to TypeAnchor for config types.
+	Type       string
+	TypeAnchor string
+	Required   bool
+	Sensitive  bool
+	Deprecated bool
+	// DeprecationMessage is rendered as text.
+	DeprecationMessage string
+	Doc                template.HTML
+	Enum               []string
+	Example            string
+	Union              string
+	Source             source
+	// Search is the lower case text matched by the search box.
+	Search   string
+	Children []*key
+}
+
+type typeEntry struct {
+	Name               string
+	Package            string
+	Anchor             string
+	Doc                template.HTML
+	Deprecated         bool
+	DeprecationMessage string
+	Source             source
+	UsedAt             []link
+}
+
+type link struct {
+	Text   string
+	Anchor string
+}
+
+// source is a source position, linked to URL if it is set.
+type source struct {
+	Text string
+	URL  string
+}
+
+type builder struct {
+	packages []loader.Package
+	renderer *doccomment.Renderer
+	options
+	// usedAt maps type anchors to the keys the types are found at.
+	usedAt map[string][]link
+}
+
+// keys returns the keys of rootType, with the keys of nested types as children.
+func (b *builder) keys(rootType loader.Type) []*key {
+	var keys []*key
+	fieldKeys := map[*loader.WalkedField]*key{}
+	sortFields := func(fields []loader.Field) []loader.Field {
+		return loader.SortFields(fields, b.order)
+	}
+	loader.Walk(rootType, b.packages, sortFields, func(field *loader.WalkedField) bool {
+		if field.Hidden || field.JSONProperty == "" {
+			return false
+		}
+		doc := printer.FieldDoc(field.Field)
+		k := &key{
+			Path:       field.Path,
+			Anchor:     doccomment.Anchor(field.Path),
+			Type:       printer.FriendlyTypeName(field.Type),
+			Required:   field.JSONRequired,
+			Sensitive:  field.Sensitive,
+			Deprecated: field.Deprecated != nil,
+			Doc:        b.html(field.Owner.Package, doc),
+			Enum:       field.Enum,
+			Example:    field.Example,
+			Source:     b.source(field.Owner.Package, field.Position),
+			Search:     strings.ToLower(field.Path + " " + doc),
+		}
+		if field.Deprecated != nil {
+			k.DeprecationMessage = field.Deprecated.Message
+		}
+		if field.Owner.Union != nil {
+			k.Union = printer.UnionAnnotation(field.Owner.Union, field.Field)
+		}
+		if field.Elem != nil {
+			k.TypeAnchor = typeAnchor(*field.Elem)
+			b.usedAt[k.TypeAnchor] = append(b.usedAt[k.TypeAnchor], link{Text: field.Path, Anchor: k.Anchor})
+		}
+
+		if parent, ok := fieldKeys[field.Parent]; ok {
+			parent.Children = append(parent.Children, k)
+		} else {
+			keys = append(keys, k)
+		}
+		fieldKeys[field] = k
+		return true
+	})
+	return keys
+}
+
+// html renders doc from the package with path pkgPath as HTML.
+func (b *builder) html(pkgPath, doc string) template.HTML {
+	if strings.TrimSpace(doc) == "" {
+		return ""
+	}
+	// The doc comment printer escapes the text of the doc.
+	return template.HTML(b.renderer.In(pkgPath).HTML(doc))
+}
+
+func (b *builder) source(pkgPath string, position loader.Position) source {
+	if !position.IsValid() {
+		return source{}
+	}
+	s := source{Text: filepath.Base(position.Filename) + ":" + strconv.Itoa(position.Line)}
+	if b.sourceURL != nil {
+		s.URL = b.sourceURL(pkgPath, position)
+	}
+	return s
+}
+
+// typeAnchor returns the anchor of the entry of t in the types section. Key anchors never contain
+// dots, so the anchors do not clash. Types are qualified by their package, with slashes replaced by
+// dots so the anchors need no escaping in links.
+func typeAnchor(t loader.Type) string {
+	return "type." + strings.ReplaceAll(t.Package, "/", ".") + "." + t.Name
+}
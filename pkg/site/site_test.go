@@ -0,0 +1,84 @@
+package site_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/site"
+)
+
+var _ = Describe("Site", func() {
+	var (
+		rootType loader.Type
+		packages []loader.Package
+	)
+
+	BeforeEach(func() {
+		var err error
+		packages, err = loader.New([]string{"github.com/jimmidyson/prettyconf/pkg/site/testdata"}, logger).Load()
+		Expect(err).NotTo(HaveOccurred())
+		var found bool
+		rootType, found = loader.FindType(packages, "github.com/jimmidyson/prettyconf/pkg/site/testdata", "Config")
+		Expect(found).To(BeTrue())
+	})
+
+	It("writes a reference page", func() {
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "index.html"))
+		Expect(err).NotTo(HaveOccurred())
+
+		sourceURL := func(pkgPath string, position loader.Position) string {
+			return "https://example.com/" + pkgPath + "/" + filepath.Base(position.Filename) + "#L" + strconv.Itoa(position.Line)
+		}
+		var buf bytes.Buffer
+		Expect(site.Write(&buf, rootType, packages, site.WithSourceURL(sourceURL))).To(Succeed())
+		Expect(buf.String()).To(Equal(string(expected)))
+	})
+
+	It("generates a directory linking to source files", func() {
+		dir, err := ioutil.TempDir("", "site")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		Expect(site.Generate(filepath.Join(dir, "docs"), rootType, packages, site.WithTitle("App config"))).To(Succeed())
+		page, err := ioutil.ReadFile(filepath.Join(dir, "docs", site.IndexFile))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(page)).To(ContainSubstring("<title>App config</title>"))
+
+		source, err := filepath.Abs(filepath.Join("testdata", "test_types.go"))
+		Expect(err).NotTo(HaveOccurred())
+		rel, err := filepath.Rel(filepath.Join(dir, "docs"), source)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(page)).To(ContainSubstring(`<a href="` + filepath.ToSlash(rel) + `">test_types.go:8</a>`))
+		Expect(strings.Count(string(page), "<script>")).To(Equal(1))
+		Expect(string(page)).NotTo(MatchRegexp(`(src|href)="https?://`))
+	})
+
+	It("gives same named types in different packages their own entries", func() {
+		packages, err := loader.New([]string{
+			"github.com/jimmidyson/prettyconf/pkg/site/testdata",
+			"github.com/jimmidyson/prettyconf/pkg/site/testdata/admin",
+		}, logger).Load()
+		Expect(err).NotTo(HaveOccurred())
+		rootType, found := loader.FindType(packages, "github.com/jimmidyson/prettyconf/pkg/site/testdata/admin", "Config")
+		Expect(found).To(BeTrue())
+
+		var buf bytes.Buffer
+		Expect(site.Write(&buf, rootType, packages)).To(Succeed())
+		for _, anchor := range []string{
+			"type.github.com.jimmidyson.prettyconf.pkg.site.testdata.Server",
+			"type.github.com.jimmidyson.prettyconf.pkg.site.testdata.admin.Server",
+		} {
+			Expect(strings.Count(buf.String(), `id="`+anchor+`"`)).To(Equal(1))
+			Expect(buf.String()).To(ContainSubstring(`href="#` + anchor + `"`))
+		}
+		Expect(buf.String()).To(ContainSubstring(`which listens on <a href="#admin-address">Server.Address</a>`))
+	})
+})
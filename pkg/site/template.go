@@ -0,0 +1,193 @@
+package site
+
+import "html/template"
+
+// pageTemplate is the reference page. Styles and scripts are inline so the page has no external
+// assets.
+var pageTemplate = template.Must(template.Must(template.New("page").Parse(pageHTML)).Parse(partialsHTML))
+
+const pageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; margin: 0; color: #1f2328; }
+header { position: sticky; top: 0; display: flex; gap: 1em; align-items: center; padding: 0.5em 2em; background: #f6f8fa; border-bottom: 1px solid #d0d7de; }
+header h1 { font-size: 1.25em; margin: 0; flex: 1; }
+header input { font-size: 1em; padding: 0.25em 0.5em; width: 20em; }
+main { max-width: 60em; padding: 1em 2em; }
+code, pre, .path, .type { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+ul.tree, ul.tree ul { list-style: none; padding-left: 1.25em; }
+ul.tree { padding-left: 0; }
+ul.tree li { margin: 0.5em 0; }
+summary, .leaf { cursor: pointer; }
+.leaf { padding-left: 1em; }
+.path { font-weight: 600; text-decoration: none; color: inherit; }
+.type { color: #57606a; }
+.badge { font-size: 0.75em; border: 1px solid; border-radius: 1em; padding: 0 0.5em; margin-left: 0.25em; }
+.required { color: #cf222e; }
+.sensitive { color: #9a6700; }
+.deprecated { color: #6e7781; }
+.details { padding-left: 1em; }
+.details p { margin: 0.25em 0; }
+.source { font-size: 0.85em; color: #57606a; }
+:target { background: #fff8c5; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<button type="button" id="expand">Expand all</button>
+<button type="button" id="collapse">Collapse all</button>
+<input type="search" id="search" placeholder="Search keys" aria-label="Search keys">
+</header>
+<main>
+{{- with .Doc}}
+<section id="overview">
+{{.}}
+</section>
+{{- end}}
+{{- if .Source.Text}}
+<p class="source">Defined in {{template "source" .Source}}</p>
+{{- end}}
+<section id="keys">
+<h2>Keys</h2>
+<ul class="tree">
+{{- template "keys" .Keys}}
+</ul>
+</section>
+<section id="example">
+<h2>Example</h2>
+<pre><code>{{.Example}}</code></pre>
+</section>
+<section id="types">
+<h2>Types</h2>
+{{- range .Types}}
+<div class="type-entry" id="{{.Anchor}}">
+<h3>{{.Name}}</h3>
+<p class="source"><code>{{.Package}}</code>{{if .Source.Text}} · {{template "source" .Source}}{{end}}</p>
+{{- with .Doc}}
+{{.}}
+{{- end}}
+{{- if .Deprecated}}
+<p class="deprecated">Deprecated{{with .DeprecationMessage}}: {{.}}{{end}}</p>
+{{- end}}
+{{- with .UsedAt}}
+<p>Used at: {{range $i, $link := .}}{{if $i}}, {{end}}<a href="#{{$link.Anchor}}"><code>{{$link.Text}}</code></a>{{end}}</p>
+{{- end}}
+</div>
+{{- end}}
+</section>
+</main>
+<script>
+(function() {
+  var search = document.getElementById("search");
+  var items = document.querySelectorAll("ul.tree li");
+  var details = document.querySelectorAll("ul.tree details");
+
+  function setOpen(open) {
+    for (var i = 0; i < details.length; i++) {
+      details[i].open = open;
+    }
+  }
+
+  // reveal shows el and opens the keys it is nested in.
+  function reveal(el) {
+    for (; el; el = el.parentElement) {
+      if (el.tagName === "LI") {
+        el.hidden = false;
+      }
+      if (el.tagName === "DETAILS") {
+        el.open = true;
+      }
+    }
+  }
+
+  search.addEventListener("input", function() {
+    var query = search.value.trim().toLowerCase();
+    for (var i = 0; i < items.length; i++) {
+      items[i].hidden = query !== "";
+    }
+    if (query === "") {
+      return;
+    }
+    for (var i = 0; i < items.length; i++) {
+      if (items[i].getAttribute("data-search").indexOf(query) !== -1) {
+        reveal(items[i]);
+      }
+    }
+  });
+
+  document.getElementById("expand").addEventListener("click", function() { setOpen(true); });
+  document.getElementById("collapse").addEventListener("click", function() { setOpen(false); });
+
+  function revealTarget() {
+    var target = document.getElementById(decodeURIComponent(location.hash.slice(1)));
+    if (target) {
+      reveal(target);
+      target.scrollIntoView();
+    }
+  }
+  window.addEventListener("hashchange", revealTarget);
+  revealTarget();
+})();
+</script>
+</body>
+</html>
+`
+
+const partialsHTML = `
+{{define "keys"}}
+{{- range .}}
+<li id="{{.Anchor}}" data-search="{{.Search}}">
+{{- if .Children}}
+<details open>
+<summary>{{template "header" .}}</summary>
+{{- template "details" .}}
+<ul>
+{{- template "keys" .Children}}
+</ul>
+</details>
+{{- else}}
+<div class="leaf">{{template "header" .}}</div>
+{{- template "details" .}}
+{{- end}}
+</li>
+{{- end}}
+{{- end}}
+{{define "header" -}}
+<a class="path" href="#{{.Anchor}}">{{.Path}}</a>
+<span class="type">{{if .TypeAnchor}}<a href="#{{.TypeAnchor}}">{{.Type}}</a>{{else}}{{.Type}}{{end}}</span>
+{{- if .Required}} <span class="badge required">required</span>{{end}}
+{{- if .Sensitive}} <span class="badge sensitive">sensitive</span>{{end}}
+{{- if .Deprecated}} <span class="badge deprecated">deprecated</span>{{end}}
+{{- end}}
+{{define "details"}}
+<div class="details">
+{{- with .Doc}}
+{{.}}
+{{- end}}
+{{- with .Enum}}
+<p>Allowed values: {{range $i, $value := .}}{{if $i}}, {{end}}<code>{{$value}}</code>{{end}}</p>
+{{- end}}
+{{- with .Example}}
+<p>Example: <code>{{.}}</code></p>
+{{- end}}
+{{- if .Deprecated}}
+<p class="deprecated">Deprecated{{with .DeprecationMessage}}: {{.}}{{end}}</p>
+{{- end}}
+{{- with .Union}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .Source.Text}}
+<p class="source">Defined in {{template "source" .Source}}</p>
+{{- end}}
+</div>
+{{- end}}
+{{define "source" -}}
+{{if .URL}}<a href="{{.URL}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}
+{{- end}}
+`
@@ -0,0 +1,17 @@
+package admin
+
+import "github.com/jimmidyson/prettyconf/pkg/site/testdata"
+
+// Config is the config of an app with an admin server.
+type Config struct {
+	// App configures the app.
+	App testdata.Config `json:"app"`
+	// Admin is the admin server, which listens on [Server.Address].
+	Admin Server `json:"admin"`
+}
+
+// Server is the admin server.
+type Server struct {
+	// Address is the address to listen on.
+	Address string `json:"address"`
+}
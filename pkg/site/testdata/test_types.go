@@ -0,0 +1,53 @@
+package testdata
+
+import "time"
+
+// Config is the config of an app.
+//
+// Servers are configured in [Config.Servers].
+type Config struct {
+	// Name is the name of the app.
+	Name string `json:"name" prettyconf:"example=billing"`
+	// LogLevel is the level to log at.
+	// +enum=debug,info
+	LogLevel string `json:"logLevel,omitempty"`
+	// Servers are the servers to listen on.
+	Servers []Server `json:"servers,omitempty"`
+	// Storage configures where data is stored.
+	Storage Storage `json:"storage"`
+	// Labels are added to every metric.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Timeout is how long to wait for requests.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Verbose enables verbose logging.
+	//
+	// Deprecated: Use LogLevel instead.
+	Verbose bool `json:"verbose,omitempty"`
+	// Internal is not shown.
+	Internal string `json:"internal,omitempty" prettyconf:"hidden"`
+}
+
+// Server is a server to listen on.
+type Server struct {
+	// Address is the address to listen on, e.g. `:8080`.
+	Address string `json:"address"`
+	// Password protects the server.
+	// +sensitive
+	Password string `json:"password,omitempty"`
+}
+
+// Storage configures where data is stored.
+// +union:discriminator=type
+type Storage struct {
+	// Type selects the storage backend.
+	Type string `json:"type"`
+	// S3 configures S3 storage.
+	// +union:member=s3
+	S3 *S3 `json:"s3,omitempty"`
+}
+
+// S3 configures S3 storage.
+type S3 struct {
+	// Bucket is the bucket to store data in.
+	Bucket string `json:"bucket"`
+}
@@ -0,0 +1,205 @@
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// ChangeKind is the kind of a Change.
+type ChangeKind string
+
+const (
+	KeyAdded          ChangeKind = "keyAdded"
+	KeyRemoved        ChangeKind = "keyRemoved"
+	KeyRenamed        ChangeKind = "keyRenamed"
+	TypeChanged       ChangeKind = "typeChanged"
+	BecameRequired    ChangeKind = "becameRequired"
+	BecameOptional    ChangeKind = "becameOptional"
+	EnumValuesRemoved ChangeKind = "enumValuesRemoved"
+	EnumValuesAdded   ChangeKind = "enumValuesAdded"
+	DefaultChanged    ChangeKind = "defaultChanged"
+	KeyDeprecated     ChangeKind = "keyDeprecated"
+)
+
+// Change is a difference between two snapshots.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Path is the key path in the new snapshot, or in the old snapshot for removed keys.
+	Path string `json:"path"`
+	// OldPath is the key path in the old snapshot of renamed keys and of keys below them.
+	OldPath string `json:"oldPath,omitempty"`
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+	// Breaking is true if configs that were valid, or behaved a certain way, before the change
+	// may not be valid or may behave differently after it.
+	Breaking bool `json:"breaking"`
+}
+
+func (c Change) String() string {
+	severity := "compatible"
+	if c.Breaking {
+		severity = "breaking"
+	}
+	switch c.Kind {
+	case KeyAdded:
+		return fmt.Sprintf("%s: %s added", severity, c.Path)
+	case KeyRemoved:
+		return fmt.Sprintf("%s: %s removed", severity, c.Path)
+	case KeyRenamed:
+		return fmt.Sprintf("%s: %s renamed to %s", severity, c.OldPath, c.Path)
+	case BecameRequired:
+		return fmt.Sprintf("%s: %s is now required", severity, c.Path)
+	case BecameOptional:
+		return fmt.Sprintf("%s: %s is now optional", severity, c.Path)
+	case KeyDeprecated:
+		return fmt.Sprintf("%s: %s is deprecated", severity, c.Path)
+	case TypeChanged:
+		return fmt.Sprintf("%s: type of %s changed from %s to %s", severity, c.Path, c.Old, c.New)
+	case EnumValuesRemoved:
+		return fmt.Sprintf("%s: values %s of %s removed", severity, c.Old, c.Path)
+	case EnumValuesAdded:
+		return fmt.Sprintf("%s: values %s of %s added", severity, c.New, c.Path)
+	case DefaultChanged:
+		return fmt.Sprintf("%s: default of %s changed from %s to %s", severity, c.Path, c.Old, c.New)
+	default:
+		return fmt.Sprintf("%s: %s %s", severity, c.Path, c.Kind)
+	}
+}
+
+// HasBreaking returns true if any of the changes is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff returns the changes from old to new. Removed keys are reported as renamed if a key in new
+// has a `+renamedFrom` or `+movedFrom` marker naming them, and keys below renamed keys are compared
+// with their old counterparts. Renames are breaking, as old configs must be migrated.
+func Diff(old, new Snapshot) []Change {
+	renames := map[string]string{}
+	for _, key := range new.Keys {
+		for _, from := range key.RenamedFrom {
+			renames[loader.JoinKeyPath(loader.ParentKeyPath(key.Path), from)] = key.Path
+		}
+		for _, from := range key.MovedFrom {
+			renames[from] = key.Path
+		}
+	}
+
+	var changes []Change
+	matched := map[string]bool{}
+	for _, oldKey := range old.Keys {
+		newPath, renamed := renamedPath(oldKey.Path, renames)
+		newKey, found := new.Key(newPath)
+		if !found {
+			changes = append(changes, Change{Kind: KeyRemoved, Path: oldKey.Path, Breaking: true})
+			continue
+		}
+		matched[newPath] = true
+		if renamed {
+			if _, isRename := renames[oldKey.Path]; isRename {
+				changes = append(changes, Change{Kind: KeyRenamed, Path: newPath, OldPath: oldKey.Path, Breaking: true})
+			}
+		}
+		for _, c := range compareKeys(oldKey, newKey) {
+			if renamed {
+				c.OldPath = oldKey.Path
+			}
+			changes = append(changes, c)
+		}
+	}
+
+	for _, newKey := range new.Keys {
+		if matched[newKey.Path] {
+			continue
+		}
+		// New required keys break configs that do not set them, unless their parent is new too.
+		parent := parentKey(newKey.Path)
+		breaking := newKey.Required && (parent == "" || matched[parent])
+		changes = append(changes, Change{Kind: KeyAdded, Path: newKey.Path, New: newKey.Type, Breaking: breaking})
+	}
+	return changes
+}
+
+// renamedPath returns the path in the new snapshot of the old key path, following the rename of
+// the key or of the longest renamed prefix of its path.
+func renamedPath(path string, renames map[string]string) (string, bool) {
+	for prefix := path; prefix != ""; prefix = loader.ParentKeyPath(prefix) {
+		if newPrefix, ok := renames[prefix]; ok {
+			return newPrefix + path[len(prefix):], true
+		}
+	}
+	return path, false
+}
+
+func compareKeys(oldKey, newKey Key) []Change {
+	var changes []Change
+	oldShape, newShape := oldKey.Shape, newKey.Shape
+	if oldShape == "" || newShape == "" {
+		oldShape, newShape = oldKey.Type, newKey.Type
+	}
+	if oldShape != newShape {
+		changes = append(changes, Change{Kind: TypeChanged, Path: newKey.Path, Old: oldKey.Type, New: newKey.Type, Breaking: true})
+	}
+	if !oldKey.Required && newKey.Required {
+		changes = append(changes, Change{Kind: BecameRequired, Path: newKey.Path, Breaking: true})
+	}
+	if oldKey.Required && !newKey.Required {
+		changes = append(changes, Change{Kind: BecameOptional, Path: newKey.Path})
+	}
+	if len(oldKey.Enum) > 0 || len(newKey.Enum) > 0 {
+		if removed := missing(oldKey.Enum, newKey.Enum); len(removed) > 0 && len(newKey.Enum) > 0 {
+			changes = append(changes, Change{Kind: EnumValuesRemoved, Path: newKey.Path, Old: strings.Join(removed, ","), Breaking: true})
+		}
+		if added := missing(newKey.Enum, oldKey.Enum); len(added) > 0 && len(oldKey.Enum) > 0 {
+			changes = append(changes, Change{Kind: EnumValuesAdded, Path: newKey.Path, New: strings.Join(added, ",")})
+		}
+	}
+	if len(oldKey.Default) > 0 && len(newKey.Default) > 0 && !defaultsEqual(oldKey.Default, newKey.Default) {
+		changes = append(changes, Change{Kind: DefaultChanged, Path: newKey.Path, Old: string(oldKey.Default), New: string(newKey.Default), Breaking: true})
+	}
+	if oldKey.Deprecated == nil && newKey.Deprecated != nil {
+		changes = append(changes, Change{Kind: KeyDeprecated, Path: newKey.Path, New: newKey.Deprecated.Message})
+	}
+	return changes
+}
+
+// missing returns the values in a that are not in b.
+func missing(a, b []string) []string {
+	var values []string
+	for _, v := range a {
+		found := false
+		for _, other := range b {
+			if v == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parentKey returns the path of the key holding the value at path, e.g. `servers` for
+// `servers[*].name`.
+func parentKey(path string) string {
+	parent := loader.ParentKeyPath(path)
+	for {
+		switch {
+		case strings.HasSuffix(parent, "[*]"):
+			parent = strings.TrimSuffix(parent, "[*]")
+		case strings.HasSuffix(parent, ".*"):
+			parent = strings.TrimSuffix(parent, ".*")
+		default:
+			return parent
+		}
+	}
+}
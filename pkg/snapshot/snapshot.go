@@ -0,0 +1,177 @@
+// Package snapshot records the keys of a config type in a file and compares snapshots taken from
+// different versions of the type, classifying the changes that would break existing configs.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/printer"
+)
+
+// Version is the version of the snapshot file format written by Write.
+const Version = 1
+
+// Snapshot is the set of keys of a config type at a point in time.
+type Snapshot struct {
+	Version int `json:"version"`
+	// Root is the import path qualified name of the root config type.
+	Root string `json:"root"`
+	Keys []Key  `json:"keys"`
+}
+
+// Key is a config key.
+type Key struct {
+	// Path is the dot separated key path. Slice elements are addressed as `key[*]` and map values
+	// as `key.*`.
+	Path string `json:"path"`
+	// Owner is the import path qualified name of the type that declares the field.
+	Owner string `json:"owner"`
+	// Field is the Go name of the field.
+	Field string `json:"field"`
+	// Type describes the type of the value, as printed in type annotations.
+	Type string `json:"type"`
+	// Shape describes the type of the value as it is serialized, naming every struct `object`, and
+	// is compared to find type changes. Keys without a shape are compared by Type.
+	Shape    string   `json:"shape,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+	// Default is the JSON value of the key in the defaults the snapshot was taken with.
+	Default     json.RawMessage `json:"default,omitempty"`
+	Doc         string          `json:"doc,omitempty"`
+	Deprecated  *Deprecation    `json:"deprecated,omitempty"`
+	RenamedFrom []string        `json:"renamedFrom,omitempty"`
+	MovedFrom   []string        `json:"movedFrom,omitempty"`
+}
+
+// Deprecation describes a deprecated key.
+type Deprecation struct {
+	Message     string `json:"message,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// For loads the type of conf and takes a snapshot of it, using conf as the defaults.
+func For(conf interface{}, logger logr.Logger) (Snapshot, error) {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return New(rootType, packages, conf)
+}
+
+// New takes a snapshot of the keys reachable from rootType. If defaults is not nil the value of
+// each key in it is recorded as the default of the key. Only keys that do not hold structs have
+// defaults, and keys in slices and maps have none. Keys without a value in defaults have the
+// default set with the `+default` marker of their field, if any, so that snapshots taken from
+// source can be compared for changed defaults.
+func New(rootType loader.Type, packages []loader.Package, defaults interface{}) (Snapshot, error) {
+	var defaultValues map[string]interface{}
+	if defaults != nil {
+		marshalled, err := json.Marshal(defaults)
+		if err != nil {
+			return Snapshot{}, errors.Wrap(err, "failed to marshal defaults to json")
+		}
+		if err := json.Unmarshal(marshalled, &defaultValues); err != nil {
+			return Snapshot{}, errors.Wrap(err, "failed to unmarshal defaults")
+		}
+	}
+
+	snapshot := Snapshot{Version: Version, Root: rootType.Package + "." + rootType.Name, Keys: []Key{}}
+	// fieldDefaults holds the defaults of the values of walked fields that hold structs.
+	fieldDefaults := map[*loader.WalkedField]map[string]interface{}{nil: defaultValues}
+	var err error
+	loader.Walk(rootType, packages, nil, func(field *loader.WalkedField) bool {
+		if err != nil {
+			return false
+		}
+		key := Key{
+			Path:        field.Path,
+			Owner:       field.Owner.Package + "." + field.Owner.Name,
+			Field:       field.Name,
+			Type:        printer.FriendlyTypeName(field.Type),
+			Shape:       printer.StructuralTypeName(field.Type),
+			Required:    field.JSONRequired,
+			Enum:        field.Enum,
+			Doc:         field.Doc,
+			RenamedFrom: field.Markers.List(loader.RenamedFromMarker),
+			MovedFrom:   field.Markers.List(loader.MovedFromMarker),
+		}
+		if field.Deprecated != nil {
+			key.Deprecated = &Deprecation{Message: field.Deprecated.Message, Replacement: field.Deprecated.Replacement}
+		}
+		defaultValue, hasDefault := fieldDefaults[field.Parent][field.JSONProperty]
+		if hasDefault && field.Elem == nil {
+			var marshalled []byte
+			if marshalled, err = json.Marshal(defaultValue); err != nil {
+				err = errors.Wrapf(err, "failed to marshal default of %s", field.Path)
+				return false
+			}
+			key.Default = marshalled
+		} else if marker, ok := field.Markers.Get(loader.DefaultMarker); ok {
+			key.Default = markerDefault(marker)
+		}
+		snapshot.Keys = append(snapshot.Keys, key)
+
+		if field.Elem != nil && field.ElemPath == field.Path {
+			fieldDefaults[field], _ = defaultValue.(map[string]interface{})
+		}
+		return true
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// Key returns the key with path and whether it was found.
+func (s Snapshot) Key(path string) (Key, bool) {
+	for _, key := range s.Keys {
+		if key.Path == path {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// Write writes the snapshot to w as indented JSON.
+func (s Snapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(s), "failed to write snapshot")
+}
+
+// Read reads a snapshot written by Write.
+func Read(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return Snapshot{}, errors.Wrap(err, "failed to read snapshot")
+	}
+	if s.Version != Version {
+		return Snapshot{}, errors.Errorf("unsupported snapshot version %d, expected %d", s.Version, Version)
+	}
+	return s, nil
+}
+
+// markerDefault returns the JSON value of a `+default` marker, which is the marker value if it is
+// valid JSON or the marker value as a JSON string otherwise.
+func markerDefault(marker string) json.RawMessage {
+	if json.Valid([]byte(marker)) {
+		return json.RawMessage(marker)
+	}
+	quoted, _ := json.Marshal(marker)
+	return quoted
+}
+
+// defaultsEqual compares two JSON values ignoring formatting.
+func defaultsEqual(a, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return compactA.String() == compactB.String()
+}
@@ -0,0 +1,92 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+	"github.com/jimmidyson/prettyconf/pkg/snapshot"
+	v1 "github.com/jimmidyson/prettyconf/pkg/snapshot/testdata/v1"
+	v2 "github.com/jimmidyson/prettyconf/pkg/snapshot/testdata/v2"
+)
+
+var _ = Describe("Snapshot", func() {
+	It("writes and reads snapshots", func() {
+		s, err := snapshot.For(&v1.Config{Listen: ":8080", Timeout: 30, Cache: &v1.CacheConfig{Size: 10}}, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(s.Write(&buf)).To(Succeed())
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "v1.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal(string(expected)))
+
+		read, err := snapshot.Read(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		buf.Reset()
+		Expect(read.Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(Equal(string(expected)))
+	})
+
+	It("rejects unknown versions", func() {
+		_, err := snapshot.Read(bytes.NewBufferString(`{"version": 2}`))
+		Expect(err).To(MatchError("unsupported snapshot version 2, expected 1"))
+	})
+
+	It("classifies changes", func() {
+		old, err := snapshot.For(v1.Config{Listen: ":8080", Mode: "fast", Cache: &v1.CacheConfig{Size: 10}}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		new, err := snapshot.For(v2.Config{ListenAddress: ":9090", Mode: "fast", Caching: &v2.CacheConfig{Size: 10}}, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		changes := snapshot.Diff(old, new)
+		Expect(snapshot.HasBreaking(changes)).To(BeTrue())
+		var descriptions []string
+		for _, c := range changes {
+			descriptions = append(descriptions, c.String())
+		}
+		Expect(descriptions).To(Equal([]string{
+			"breaking: listen renamed to listenAddress",
+			`breaking: default of listenAddress changed from ":8080" to ":9090"`,
+			"breaking: type of timeout changed from int to string",
+			"breaking: mode is now required",
+			"breaking: values slow of mode removed",
+			"compatible: values trace of level added",
+			`breaking: default of level changed from "info" to "debug"`,
+			"breaking: cache renamed to caching",
+			"compatible: caching.size is now optional",
+			"breaking: backends[*].endpoint renamed to backends[*].url",
+			"breaking: legacy removed",
+			"compatible: name is deprecated",
+			"breaking: caching.ttl added",
+			"breaking: backends[*].weight added",
+			"breaking: region added",
+		}))
+		Expect(changes[8].OldPath).To(Equal("cache.size"))
+	})
+
+	It("compares default markers of snapshots taken from source", func() {
+		oldType, oldPackages, err := loader.LoadFor(v1.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		old, err := snapshot.New(oldType, oldPackages, nil)
+		Expect(err).NotTo(HaveOccurred())
+		newType, newPackages, err := loader.LoadFor(v2.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		new, err := snapshot.New(newType, newPackages, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(snapshot.Diff(old, new)).To(ContainElement(snapshot.Change{
+			Kind: snapshot.DefaultChanged, Path: "level", Old: `"info"`, New: `"debug"`, Breaking: true,
+		}))
+	})
+
+	It("reports no changes for identical snapshots", func() {
+		s, err := snapshot.For(v1.Config{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot.Diff(s, s)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,44 @@
+package v1
+
+// Config is the first version of a config.
+type Config struct {
+	// Listen is the address to listen on.
+	Listen string `json:"listen"`
+	// Timeout is the timeout in seconds.
+	Timeout int `json:"timeout,omitempty"`
+	// Mode is the mode to run in.
+	// +enum=fast,slow,safe
+	Mode string `json:"mode,omitempty"`
+	// Level is the log level.
+	// +enum=info,debug
+	// +default="info"
+	Level string `json:"level,omitempty"`
+	// Cache configures caching.
+	Cache *CacheConfig `json:"cache,omitempty"`
+	// Backends are the backends to use.
+	Backends []Backend `json:"backends,omitempty"`
+	// Legacy is removed in the next version.
+	Legacy bool `json:"legacy,omitempty"`
+	// Server configures the server.
+	Server ServerConfig `json:"server,omitempty"`
+	// Name is the instance name.
+	Name string `json:"name,omitempty"`
+}
+
+// CacheConfig configures caching.
+type CacheConfig struct {
+	// Size is the cache size.
+	Size int `json:"size"`
+}
+
+// Backend is a backend.
+type Backend struct {
+	// Endpoint is the backend endpoint.
+	Endpoint string `json:"endpoint"`
+}
+
+// ServerConfig configures the server. Only its Go name differs between versions.
+type ServerConfig struct {
+	// Workers is the number of workers.
+	Workers int `json:"workers,omitempty"`
+}
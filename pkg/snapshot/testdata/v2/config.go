@@ -0,0 +1,53 @@
+package v2
+
+// Config is the second version of a config.
+type Config struct {
+	// ListenAddress is the address to listen on.
+	// +renamedFrom=listen
+	ListenAddress string `json:"listenAddress"`
+	// Timeout is the timeout.
+	Timeout string `json:"timeout,omitempty"`
+	// Mode is the mode to run in.
+	// +enum=fast,safe
+	Mode string `json:"mode"`
+	// Level is the log level.
+	// +enum=info,debug,trace
+	// +default=debug
+	Level string `json:"level,omitempty"`
+	// Caching configures caching.
+	// +renamedFrom=cache
+	Caching *CacheConfig `json:"caching,omitempty"`
+	// Backends are the backends to use.
+	Backends []Backend `json:"backends,omitempty"`
+	// Server configures the server.
+	Server Server `json:"server,omitempty"`
+	// Name is the instance name.
+	//
+	// Deprecated: Names are generated.
+	Name string `json:"name,omitempty"`
+	// Region is the region to run in.
+	Region string `json:"region"`
+}
+
+// CacheConfig configures caching.
+type CacheConfig struct {
+	// Size is the cache size.
+	Size int `json:"size,omitempty"`
+	// TTL is how long entries are cached for.
+	TTL string `json:"ttl"`
+}
+
+// Backend is a backend.
+type Backend struct {
+	// URL is the backend URL.
+	// +renamedFrom=endpoint
+	URL string `json:"url"`
+	// Weight is the backend weight.
+	Weight int `json:"weight"`
+}
+
+// Server configures the server. Only its Go name differs between versions.
+type Server struct {
+	// Workers is the number of workers.
+	Workers int `json:"workers,omitempty"`
+}
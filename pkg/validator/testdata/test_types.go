@@ -0,0 +1,51 @@
+package testdata
+
+// Config holds unions.
+type Config struct {
+	// Storage configures storage.
+	Storage StorageConfig `json:"storage"`
+	// Caches configure caches.
+	Caches []CacheConfig `json:"caches,omitempty"`
+}
+
+// StorageConfig configures one storage backend.
+// +union:discriminator=type
+type StorageConfig struct {
+	// Type selects the backend.
+	Type string `json:"type"`
+	// S3 configures S3 storage.
+	// +union:member=s3
+	S3 *Bucket `json:"s3,omitempty"`
+	// GCS configures GCS storage.
+	// +union:member=gcs
+	GCS *Bucket `json:"gcs,omitempty"`
+}
+
+// Bucket holds bucket details.
+type Bucket struct {
+	// Name is the bucket name.
+	Name string `json:"name"`
+}
+
+// CacheConfig configures one cache.
+// +union
+type CacheConfig struct {
+	// Memory configures an in-memory cache.
+	Memory *Bucket `json:"memory,omitempty"`
+	// Disk configures an on-disk cache.
+	Disk *Bucket `json:"disk,omitempty"`
+}
+
+// DeprecatedConfig holds deprecated fields.
+type DeprecatedConfig struct {
+	// Address is the address to listen on.
+	//
+	// Deprecated: Use Listen instead.
+	Address string `json:"address,omitempty"`
+	// Port is the port to listen on.
+	//
+	// Deprecated: The port is part of Listen.
+	Port int `json:"port,omitempty"`
+	// Listen is the address to listen on.
+	Listen string `json:"listen"`
+}
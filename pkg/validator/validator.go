@@ -0,0 +1,241 @@
+package validator
+
+import (
+	"fmt"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jimmidyson/prettyconf/pkg/loader"
+)
+
+// Severity is the severity of an Issue.
+type Severity string
+
+const (
+	// SeverityError is used for issues that make a config invalid.
+	SeverityError Severity = "error"
+	// SeverityWarning is used for issues that do not make a config invalid but should be fixed.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found in a config document.
+type Issue struct {
+	// Path is the key path the issue was found at, e.g. `servers[0].url`.
+	Path     string
+	Line     int
+	Column   int
+	Severity Severity
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s: %s", i.Line, i.Column, i.Path, i.Severity, i.Message)
+}
+
+// HasErrors returns true if any of the issues has SeverityError.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the YAML or JSON document data against the type of conf, loading its package.
+func Validate(data []byte, conf interface{}, logger logr.Logger) ([]Issue, error) {
+	rootType, packages, err := loader.LoadFor(conf, logger)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateType(data, rootType, packages)
+}
+
+// ValidateType validates the YAML or JSON document data against the loaded type rootType. Types
+// referenced by rootType are looked up in packages.
+func ValidateType(data []byte, rootType loader.Type, packages []loader.Package) ([]Issue, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, errors.Wrap(err, "failed to parse config document")
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	v := &validator{packages: packages}
+	v.validateMapping(document.Content[0], rootType, "")
+	return v.issues, nil
+}
+
+type validator struct {
+	packages []loader.Package
+	issues   []Issue
+}
+
+func (v *validator) addIssue(node *yaml.Node, path string, severity Severity, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{
+		Path:     path,
+		Line:     node.Line,
+		Column:   node.Column,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validateMapping(node *yaml.Node, pkgType loader.Type, path string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		field, found := findField(pkgType, keyNode.Value)
+		if !found {
+			continue
+		}
+		fieldPath := loader.JoinKeyPath(path, keyNode.Value)
+		if field.Deprecated != nil {
+			v.addDeprecationIssue(keyNode, fieldPath, field.Deprecated, pkgType)
+		}
+		v.validateValue(valueNode, field.Type, fieldPath)
+	}
+	if pkgType.Union != nil {
+		v.validateUnion(node, pkgType.Union, path)
+	}
+}
+
+func (v *validator) addDeprecationIssue(node *yaml.Node, path string, deprecation *loader.Deprecation, pkgType loader.Type) {
+	// Deprecations usually name fields by their Go names, so report their keys instead.
+	message := "is deprecated"
+	switch {
+	case deprecation.Replacement != "":
+		replacement := deprecation.Replacement
+		for _, f := range pkgType.Fields {
+			if f.Name == replacement {
+				replacement = f.JSONProperty
+				break
+			}
+		}
+		message += ", use " + replacement + " instead"
+	case deprecation.Message != "":
+		deprecationMessage := deprecation.Message
+		for _, f := range pkgType.Fields {
+			if f.JSONProperty != "" {
+				deprecationMessage = regexp.MustCompile(`\b`+regexp.QuoteMeta(f.Name)+`\b`).ReplaceAllLiteralString(deprecationMessage, f.JSONProperty)
+			}
+		}
+		message += ": " + deprecationMessage
+	}
+	v.addIssue(node, path, SeverityWarning, "%s", message)
+}
+
+func (v *validator) validateValue(node *yaml.Node, valueType types.Type, path string) {
+	switch t := valueType.(type) {
+	case *types.Pointer:
+		v.validateValue(node, t.Elem(), path)
+	case *types.Named:
+		pkgType, found := loader.FindNamedType(v.packages, t)
+		if !found {
+			v.validateValue(node, t.Underlying(), path)
+			return
+		}
+		v.validateMapping(node, pkgType, path)
+	case *types.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.validateValue(node.Content[i+1], t.Elem(), loader.JoinKeyPath(path, node.Content[i].Value))
+		}
+	case *types.Slice:
+		v.validateSequence(node, t.Elem(), path)
+	case *types.Array:
+		v.validateSequence(node, t.Elem(), path)
+	}
+}
+
+func (v *validator) validateSequence(node *yaml.Node, elemType types.Type, path string) {
+	if node.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, itemNode := range node.Content {
+		v.validateValue(itemNode, elemType, loader.IndexKeyPath(path, i))
+	}
+}
+
+// validateUnion checks that exactly one member of the union is set and, if the union has a
+// discriminator, that it selects the member that is set.
+func (v *validator) validateUnion(node *yaml.Node, union *loader.Union, path string) {
+	var (
+		setMembers         []loader.UnionMember
+		setKeyNodes        []*yaml.Node
+		discriminatorNode  *yaml.Node
+		discriminatorValue string
+	)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if union.Discriminator != "" && keyNode.Value == union.Discriminator {
+			discriminatorNode, discriminatorValue = valueNode, valueNode.Value
+			continue
+		}
+		member, isMember := union.Member(keyNode.Value)
+		if !isMember || isNull(valueNode) {
+			continue
+		}
+		setMembers = append(setMembers, member)
+		setKeyNodes = append(setKeyNodes, keyNode)
+	}
+
+	memberNames := make([]string, 0, len(union.Members))
+	for _, m := range union.Members {
+		memberNames = append(memberNames, m.JSONProperty)
+	}
+	switch len(setMembers) {
+	case 0:
+		v.addIssue(node, path, SeverityError, "exactly one of %s must be set", strings.Join(memberNames, ", "))
+	case 1:
+	default:
+		v.addIssue(setKeyNodes[1], path, SeverityError, "only one of %s may be set", strings.Join(memberNames, ", "))
+	}
+
+	if union.Discriminator == "" {
+		return
+	}
+	discriminatorPath := loader.JoinKeyPath(path, union.Discriminator)
+	if discriminatorNode == nil {
+		v.addIssue(node, discriminatorPath, SeverityError, "must be one of %s", strings.Join(union.Values(), ", "))
+		return
+	}
+	valid := false
+	for _, value := range union.Values() {
+		if value == discriminatorValue {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		v.addIssue(discriminatorNode, discriminatorPath, SeverityError, "%q must be one of %s", discriminatorValue, strings.Join(union.Values(), ", "))
+		return
+	}
+	if len(setMembers) == 1 && setMembers[0].Value != discriminatorValue {
+		v.addIssue(setKeyNodes[0], loader.JoinKeyPath(path, setMembers[0].JSONProperty), SeverityError,
+			"is set but %s is %q", union.Discriminator, discriminatorValue)
+	}
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func findField(pkgType loader.Type, jsonProperty string) (loader.Field, bool) {
+	for _, f := range pkgType.Fields {
+		if f.JSONProperty == jsonProperty {
+			return f, true
+		}
+	}
+	return loader.Field{}, false
+}
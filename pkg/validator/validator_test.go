@@ -0,0 +1,99 @@
+package validator_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/jimmidyson/prettyconf/pkg/validator"
+	"github.com/jimmidyson/prettyconf/pkg/validator/testdata"
+)
+
+var _ = Describe("Validator", func() {
+	It("errors for invalid documents", func() {
+		_, err := validator.Validate([]byte("storage: [}"), testdata.Config{}, logger)
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("unions",
+		func(document string, expected []validator.Issue) {
+			issues, err := validator.Validate([]byte(document), &testdata.Config{}, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(Equal(expected))
+		},
+		Entry("valid", `
+storage:
+  type: s3
+  s3:
+    name: bucket
+caches:
+- memory: {}
+`, nil),
+		Entry("no member set", `
+storage:
+  type: s3
+`, []validator.Issue{
+			{Path: "storage", Line: 3, Column: 3, Severity: validator.SeverityError, Message: "exactly one of s3, gcs must be set"},
+		}),
+		Entry("several members set", `
+storage:
+  type: s3
+  s3: {}
+  gcs: {}
+`, []validator.Issue{
+			{Path: "storage", Line: 5, Column: 3, Severity: validator.SeverityError, Message: "only one of s3, gcs may be set"},
+		}),
+		Entry("null members are unset", `
+storage:
+  type: gcs
+  s3: null
+  gcs: {}
+`, nil),
+		Entry("invalid discriminator", `
+storage:
+  type: azure
+  s3: {}
+`, []validator.Issue{
+			{Path: "storage.type", Line: 3, Column: 9, Severity: validator.SeverityError, Message: `"azure" must be one of s3, gcs`},
+		}),
+		Entry("missing discriminator", `
+storage:
+  s3: {}
+`, []validator.Issue{
+			{Path: "storage.type", Line: 3, Column: 3, Severity: validator.SeverityError, Message: "must be one of s3, gcs"},
+		}),
+		Entry("mismatched discriminator", `
+storage:
+  type: gcs
+  s3: {}
+`, []validator.Issue{
+			{Path: "storage.s3", Line: 4, Column: 3, Severity: validator.SeverityError, Message: `is set but type is "gcs"`},
+		}),
+		Entry("unions in slices", `
+storage:
+  type: s3
+  s3: {}
+caches:
+- memory: {}
+  disk: {}
+`, []validator.Issue{
+			{Path: "caches[0]", Line: 7, Column: 3, Severity: validator.SeverityError, Message: "only one of memory, disk may be set"},
+		}),
+	)
+})
+
+var _ = Describe("Deprecations", func() {
+	It("warns about deprecated keys", func() {
+		issues, err := validator.Validate([]byte(`
+address: localhost
+port: 8080
+listen: localhost:8080
+`), testdata.DeprecatedConfig{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(Equal([]validator.Issue{
+			{Path: "address", Line: 2, Column: 1, Severity: validator.SeverityWarning, Message: "is deprecated, use listen instead"},
+			{Path: "port", Line: 3, Column: 1, Severity: validator.SeverityWarning, Message: "is deprecated: The port is part of listen."},
+		}))
+		Expect(validator.HasErrors(issues)).To(BeFalse())
+	})
+})